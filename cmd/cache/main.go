@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+	"github.com/nickmro/sakila-service-film/sakila/config"
+	"github.com/nickmro/sakila-service-film/sakila/redis"
+)
+
+const usage = `Usage:
+  cache [flags] list [pattern]  Lists the keys matching the pattern (default: *).
+  cache [flags] inspect key     Prints the type, TTL, size and entry envelope of the key.
+  cache [flags] decode key      Prints the decoded value of the key as JSON.
+  cache [flags] purge pattern   Removes the keys matching the pattern, from every service replica.
+
+Keys and patterns exclude the redis.key_prefix, such as v1::film::id:1 or v1::films*. Without a
+redis.key_prefix, the keys of the whole Redis database match, so purge requires -yes.
+
+Flags:
+`
+
+func main() {
+	config.RegisterFlags(flag.CommandLine)
+
+	yes := flag.Bool("yes", false, "Purges keys without a redis.key_prefix")
+
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	command, arg := flag.Arg(0), flag.Arg(1)
+
+	switch {
+	case command == "list" && flag.NArg() <= 2:
+		if arg == "" {
+			arg = "*"
+		}
+	case (command == "inspect" || command == "decode" || command == "purge") && flag.NArg() == 2:
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(flag.CommandLine)
+	if err != nil {
+		panic(err)
+	}
+
+	if command == "purge" && cfg.Redis.KeyPrefix == "" && !*yes {
+		fmt.Fprintln(os.Stderr, "no redis.key_prefix is set: purge would match keys of the whole Redis database, use -yes")
+		os.Exit(2)
+	}
+
+	cache, err := newCache(cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	//nolint:errcheck
+	defer cache.Close()
+
+	ctx := context.Background()
+
+	switch command {
+	case "list":
+		err = list(ctx, cache, arg)
+	case "inspect":
+		err = inspect(ctx, cache, arg)
+	case "decode":
+		err = decode(ctx, cache, arg)
+	case "purge":
+		err = purge(ctx, cache, arg)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func newCache(cfg *config.Config) (*redis.Cache, error) {
+	tlsConfig, err := cfg.Redis.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return redis.NewCache(&redis.ClientParams{
+		Mode:             cfg.Redis.Mode,
+		Addrs:            cfg.Redis.Addrs,
+		Host:             cfg.Redis.Host,
+		Port:             cfg.Redis.Port,
+		MasterName:       cfg.Redis.MasterName,
+		Username:         cfg.Redis.Username,
+		Password:         cfg.Redis.Password,
+		SentinelPassword: cfg.Redis.SentinelPassword,
+		DB:               cfg.Redis.DB,
+		TLSConfig:        tlsConfig,
+		DialTimeout:      cfg.Redis.DialTimeout,
+		ReadTimeout:      cfg.Redis.ReadTimeout,
+		WriteTimeout:     cfg.Redis.WriteTimeout,
+		KeyPrefix:        cfg.Redis.KeyPrefix,
+	})
+}
+
+func list(ctx context.Context, cache *redis.Cache, pattern string) error {
+	keys, err := cache.Keys(ctx, pattern)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		fmt.Println(key)
+	}
+
+	return nil
+}
+
+func inspect(ctx context.Context, cache *redis.Cache, key string) error {
+	info, err := cache.Inspect(ctx, key)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "key\t%s\n", info.Key)
+	fmt.Fprintf(w, "type\t%s\n", info.Type)
+	fmt.Fprintf(w, "ttl\t%s\n", formatTTL(info.TTL))
+	fmt.Fprintf(w, "size\t%d\n", info.Size)
+
+	if entry := info.Entry; entry != nil {
+		fmt.Fprintf(w, "envelope version\t%d\n", entry.EnvelopeVersion)
+		fmt.Fprintf(w, "schema version\t%d\n", entry.SchemaVersion)
+		fmt.Fprintf(w, "codec\t%s\n", entry.Codec)
+		fmt.Fprintf(w, "compression\t%s\n", entry.Compression)
+		fmt.Fprintf(w, "not found\t%t\n", entry.NotFound)
+		fmt.Fprintf(w, "fresh until\t%s\n", entry.FreshUntil.Format(time.RFC3339))
+	}
+
+	return w.Flush()
+}
+
+func decode(ctx context.Context, cache *redis.Cache, key string) error {
+	value, err := cache.Decode(ctx, key)
+	if errors.Is(err, sakila.ErrorNotFound) {
+		fmt.Println("cached not found result")
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	b, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(b))
+
+	return nil
+}
+
+func purge(ctx context.Context, cache *redis.Cache, pattern string) error {
+	keys, err := cache.Purge(ctx, pattern)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("purged %d keys\n", len(keys))
+
+	return nil
+}
+
+func formatTTL(ttl time.Duration) string {
+	if ttl < 0 {
+		return "none"
+	}
+
+	return ttl.String()
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nickmro/sakila-service-film/sakila/config"
+)
+
+const usage = `Usage:
+  config print [flags]  Prints the effective configuration, with secrets redacted.
+
+Flags:
+`
+
+func main() {
+	printFlags := flag.NewFlagSet("print", flag.ExitOnError)
+	config.RegisterFlags(printFlags)
+
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		printFlags.SetOutput(flag.CommandLine.Output())
+		printFlags.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	switch flag.Arg(0) {
+	case "print":
+		if err := printFlags.Parse(flag.Args()[1:]); err != nil {
+			panic(err)
+		}
+
+		os.Exit(printConfig(printFlags))
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func printConfig(fs *flag.FlagSet) int {
+	cfg, err := config.Load(fs)
+
+	var errs config.Errors
+	if errors.As(err, &errs) {
+		for _, err := range errs {
+			fmt.Fprintln(os.Stderr, err)
+		}
+
+		return 1
+	} else if err != nil {
+		panic(err)
+	}
+
+	if err := cfg.Print(os.Stdout); err != nil {
+		panic(err)
+	}
+
+	return 0
+}
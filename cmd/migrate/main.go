@@ -0,0 +1,220 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nickmro/sakila-service-film/sakila/config"
+	"github.com/nickmro/sakila-service-film/sakila/dataset"
+	"github.com/nickmro/sakila-service-film/sakila/log"
+	"github.com/nickmro/sakila-service-film/sakila/mysql"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+const usage = `Usage:
+  migrate [flags] up [version]       Applies the pending migrations, up to the given version.
+  migrate [flags] down [steps]       Reverts the given number of migrations (default: 1).
+  migrate [flags] status             Prints the status of the migrations.
+  migrate [flags] seed [seed flags]  Loads the standard Sakila dataset, or a synthetic one.
+
+Flags:
+`
+
+const sakilaDataFile = "sakila-data.sql"
+
+func main() {
+	seedFlags := flag.NewFlagSet("seed", flag.ExitOnError)
+	file := seedFlags.String("file", "", "load the dataset from a sakila-data.sql file or sakila-db.tar.gz archive")
+	url := seedFlags.String("url", mysql.SakilaDataURL, "download the dataset archive from the URL")
+	synthetic := seedFlags.Bool("synthetic", false, "load a generated dataset")
+	films := seedFlags.Int("films", 1000, "the number of synthetic films")
+	actors := seedFlags.Int("actors", 200, "the number of synthetic actors")
+	seed := seedFlags.Int64("seed", 1, "the synthetic dataset random seed")
+
+	config.RegisterFlags(flag.CommandLine)
+
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+		fmt.Fprintln(flag.CommandLine.Output(), "\nSeed flags:")
+		seedFlags.SetOutput(flag.CommandLine.Output())
+		seedFlags.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	cfg, err := config.Load(flag.CommandLine)
+	if err != nil {
+		panic(err)
+	}
+
+	logger, err := log.NewWriter(log.Environment(cfg.Logger))
+	if err != nil {
+		panic(err)
+	}
+
+	defer logger.Flush()
+
+	dsn, err := cfg.MySQL.DSN()
+	if err != nil {
+		panic(err)
+	}
+
+	db, err := mysql.Open(dsn)
+	if err != nil {
+		panic(err)
+	}
+
+	//nolint:errcheck
+	defer db.Close()
+
+	ctx := context.Background()
+	migrator := &mysql.Migrator{DB: db, Logger: logger}
+
+	switch flag.Arg(0) {
+	case "up":
+		err = migrator.Up(ctx, intArg(1, 0))
+	case "down":
+		err = migrator.Down(ctx, intArg(1, 1))
+	case "status":
+		err = status(ctx, migrator)
+	case "seed":
+		if err := seedFlags.Parse(flag.Args()[1:]); err != nil {
+			panic(err)
+		}
+
+		seeder := &mysql.Seeder{DB: db, Logger: logger}
+
+		if *synthetic {
+			err = seeder.SeedSynthetic(ctx, dataset.SyntheticParams{Films: *films, Actors: *actors, Seed: *seed})
+		} else {
+			err = seedStandard(ctx, seeder, *file, *url)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func intArg(i, defaultValue int) int {
+	if flag.NArg() <= i {
+		return defaultValue
+	}
+
+	value, err := strconv.Atoi(flag.Arg(i))
+	if err != nil {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	return value
+}
+
+func status(ctx context.Context, migrator *mysql.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+
+		fmt.Fprintf(w, "%06d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+
+	return w.Flush()
+}
+
+// seedStandard loads the standard dataset from a local file, or downloads the
+// dataset archive when no file is given.
+func seedStandard(ctx context.Context, seeder *mysql.Seeder, file, url string) error {
+	var r io.Reader
+
+	if file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+
+		//nolint:errcheck
+		defer f.Close()
+
+		r = f
+	} else {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+
+		//nolint:errcheck
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("download %s: %s", url, res.Status)
+		}
+
+		r = res.Body
+	}
+
+	if strings.HasSuffix(file, ".sql") {
+		return seeder.SeedScript(ctx, r)
+	}
+
+	script, err := archivedScript(r)
+	if err != nil {
+		return err
+	}
+
+	return seeder.SeedScript(ctx, script)
+}
+
+// archivedScript returns the data script of a sakila-db.tar.gz archive.
+func archivedScript(r io.Reader) (io.Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := tar.NewReader(gz)
+
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s not found in archive", sakilaDataFile)
+		} else if err != nil {
+			return nil, err
+		}
+
+		if path.Base(header.Name) == sakilaDataFile {
+			return archive, nil
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/nickmro/sakila-service-film/sakila/graphql"
+	"github.com/nickmro/sakila-service-film/sakila/mock"
+)
+
+const defaultSDLFile = "sakila/graphql/schema.graphql"
+
+const usage = `Usage:
+  schema              Prints the schema SDL.
+  schema diff [file]  Compares the schema with an SDL file (default: %s)
+                      and exits with status 1 on breaking changes.
+`
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), usage, defaultSDLFile)
+	}
+
+	flag.Parse()
+
+	// The schema is only printed, so its resolvers never call the service.
+	schema, err := graphql.NewSchema(&mock.FilmService{})
+	if err != nil {
+		panic(err)
+	}
+
+	switch flag.Arg(0) {
+	case "":
+		fmt.Print(schema.SDL())
+	case "diff":
+		file := defaultSDLFile
+		if flag.NArg() > 1 {
+			file = flag.Arg(1)
+		}
+
+		os.Exit(diff(schema, file))
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func diff(schema *graphql.Schema, file string) int {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		panic(err)
+	}
+
+	changes, err := graphql.DiffSDL(string(b), schema.SDL())
+	if err != nil {
+		panic(err)
+	}
+
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+
+	if len(graphql.BreakingChanges(changes)) > 0 {
+		return 1
+	}
+
+	return 0
+}
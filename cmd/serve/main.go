@@ -1,51 +1,111 @@
 package main
 
 import (
+	"context"
+	"expvar"
+	"flag"
 	"fmt"
 
+	"github.com/nickmro/sakila-service-film/sakila"
 	"github.com/nickmro/sakila-service-film/sakila/config"
+	"github.com/nickmro/sakila-service-film/sakila/dataset"
+	"github.com/nickmro/sakila-service-film/sakila/event"
 	"github.com/nickmro/sakila-service-film/sakila/graphql"
 	"github.com/nickmro/sakila-service-film/sakila/health"
 	"github.com/nickmro/sakila-service-film/sakila/http"
 	"github.com/nickmro/sakila-service-film/sakila/log"
 	"github.com/nickmro/sakila-service-film/sakila/mysql"
 	"github.com/nickmro/sakila-service-film/sakila/redis"
+	"github.com/nickmro/sakila-service-film/sakila/sqlite"
 
 	"github.com/go-chi/chi"
 	_ "github.com/go-sql-driver/mysql"
 )
 
 func main() {
-	env, err := config.GetEnv(".env")
+	config.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	cfg, err := config.Load(flag.CommandLine)
 	if err != nil {
 		panic(err)
 	}
 
-	logger, err := log.NewWriter(log.Environment(env.GetLogger()))
+	logger, err := log.NewWriter(log.Environment(cfg.Logger))
 	if err != nil {
 		panic(err)
 	}
 
 	defer logger.Flush()
 
-	db, err := mysql.Open(env.GetMySQLURL())
-	if err != nil {
+	if err := logger.SetLevel(cfg.LogLevel); err != nil {
 		panic(err)
 	}
 
-	//nolint:errcheck
-	defer db.Close()
+	var filmDB sakila.FilmService
 
-	err = db.Ping()
-	if err != nil {
-		panic(err)
+	var db *mysql.DB
+
+	checks := []*health.Check{}
+
+	switch cfg.Database.Driver {
+	case config.DatabaseDriverSQLite:
+		sqliteDB, err := openSQLite(context.Background(), cfg.SQLite.Path)
+		if err != nil {
+			panic(err)
+		}
+
+		// The MySQL film queries run unchanged on the sakila_sqlite3 driver.
+		sqliteFilmDB := &mysql.DB{DB: sqliteDB.DB, Logger: logger}
+
+		//nolint:errcheck
+		defer sqliteFilmDB.Close()
+
+		filmDB = &mysql.FilmService{
+			DB:     sqliteFilmDB,
+			Logger: logger,
+		}
+
+		checks = append(checks, &health.Check{
+			Name:    "sqlite",
+			Checker: sqliteDB,
+		})
+	default:
+		dsn, err := cfg.MySQL.DSN()
+		if err != nil {
+			panic(err)
+		}
+
+		db, err = mysql.Open(dsn)
+		if err != nil {
+			panic(err)
+		}
+
+		db.Logger = logger
+		db.SetMaxOpenConns(cfg.MySQL.MaxOpenConns)
+		db.SetMaxIdleConns(cfg.MySQL.MaxIdleConns)
+		db.SetConnMaxLifetime(cfg.MySQL.ConnMaxLifetime)
+
+		//nolint:errcheck
+		defer db.Close()
+
+		err = db.Ping()
+		if err != nil {
+			panic(err)
+		}
+
+		filmDB = &mysql.FilmService{
+			DB:     db,
+			Logger: logger,
+		}
+
+		checks = append(checks, &health.Check{
+			Name:    "mysql",
+			Checker: db,
+		})
 	}
 
-	cache, err := redis.NewCache(&redis.ClientParams{
-		Host:     env.GetRedisHost(),
-		Port:     env.GetRedisPort(),
-		Password: env.GetRedisPassword(),
-	})
+	cache, err := newCache(cfg, logger)
 	if err != nil {
 		panic(err)
 	}
@@ -53,32 +113,106 @@ func main() {
 	//nolint:errcheck
 	defer cache.Close()
 
-	filmDB := &mysql.FilmService{
-		DB:     db,
-		Logger: logger,
-	}
-
 	filmCache := &redis.FilmService{
 		FilmService:    filmDB,
 		Cache:          cache,
-		CacheKeyPrefix: env.GetRedisKeyPrefix(),
+		CacheKeyPrefix: cfg.Redis.KeyPrefix,
+		TTL:            cfg.Cache.TTL,
+		TTLs: redis.TTLs{
+			Film:       cfg.Cache.FilmTTL,
+			Films:      cfg.Cache.FilmsTTL,
+			FilmActors: cfg.Cache.ActorsTTL,
+		},
+		TTLJitter:   cfg.Cache.TTLJitter,
+		StaleTTL:    cfg.Cache.StaleTTL,
+		NegativeTTL: cfg.Cache.NegativeTTL,
+		Logger:      logger,
+	}
+
+	filmEvents := event.NewBus()
+
+	filmCacheInvalidator := &redis.FilmCacheInvalidator{
+		FilmService: filmCache,
+		Publisher:   filmEvents,
+		Logger:      logger,
+	}
+
+	filmEventRelay := &redis.FilmEventRelay{
+		Cache:          cache,
+		CacheKeyPrefix: cfg.Redis.KeyPrefix,
+		Publisher:      filmCacheInvalidator,
+		Logger:         logger,
+	}
+
+	go func() {
+		if err := filmEventRelay.Listen(context.Background()); err != nil {
+			logger.Error(err)
+		}
+	}()
+
+	go func() {
+		if err := cache.ListenInvalidations(context.Background()); err != nil {
+			logger.Error(err)
+		}
+	}()
+
+	cacheWarmer := startCacheWarmer(cfg, filmCache, logger)
+
+	if interval := cfg.MySQL.ChangeFeedInterval; db != nil && interval > 0 {
+		filmChangeFeed := &mysql.FilmChangeFeed{
+			DB:        db,
+			Publisher: filmEventRelay,
+			Interval:  interval,
+			Logger:    logger,
+		}
+
+		filmChangeFeedElection := &redis.Election{
+			Cache:  cache,
+			Key:    "change_feed::leader",
+			Logger: logger,
+		}
+
+		go func() {
+			if err := filmChangeFeedElection.Run(context.Background(), filmChangeFeed.Run); err != nil {
+				logger.Error(err)
+			}
+		}()
 	}
 
-	graphqlSchema, err := graphql.NewSchema(filmCache)
+	configWatcher := &config.Watcher{
+		Config:   cfg,
+		FlagSet:  flag.CommandLine,
+		Interval: cfg.WatchInterval,
+		OnReload: func(c *config.Config) {
+			filmCache.SetTTL(c.Cache.TTL)
+
+			if err := logger.SetLevel(c.LogLevel); err != nil {
+				logger.Error(err)
+			}
+		},
+		Logger: logger,
+	}
+
+	go func() {
+		if err := configWatcher.Run(context.Background()); err != nil {
+			logger.Error(err)
+		}
+	}()
+
+	graphqlSchema, err := graphql.NewSchema(filmCache.Service())
 	if err != nil {
 		panic(err)
 	}
 
-	checker, err := health.NewChecker([]*health.Check{
-		{
-			Name:    "mysql",
-			Checker: db,
-		},
-		{
-			Name:    "redis",
-			Checker: cache,
-		},
+	graphqlSchema.Events = filmEvents
+	graphqlSchema.SupportedLocales = cfg.SupportedLocales
+
+	checks = append(checks, &health.Check{
+		Name:    "redis",
+		Checker: cache,
 	})
+
+	checker, err := health.NewChecker(checks)
 	if err != nil {
 		panic(err)
 	}
@@ -87,13 +221,33 @@ func main() {
 		panic(err)
 	}
 
+	readyChecker := checker
+
+	if cacheWarmer != nil && cfg.Cache.WarmReady {
+		readyChecks := append([]*health.Check{}, checks...)
+		readyChecks = append(readyChecks, &health.Check{
+			Name:    "cache_warmer",
+			Checker: cacheWarmer,
+		})
+
+		if readyChecker, err = health.NewChecker(readyChecks); err != nil {
+			panic(err)
+		}
+
+		if err := readyChecker.Start(); err != nil {
+			panic(err)
+		}
+	}
+
 	router := chi.NewRouter()
 	router.Use(http.RequestLogger(logger))
+	router.Use(http.Locale(cfg.SupportedLocales))
 	router.Mount("/graphql", graphql.NewHandler(graphqlSchema))
 	router.Mount("/healthz", health.NewHandler(checker))
-	router.Mount("/readyz", health.NewHandler(checker))
+	router.Mount("/readyz", health.NewHandler(readyChecker))
+	router.Mount("/debug/vars", expvar.Handler())
 
-	addr := fmt.Sprintf(":%s", env.GetPort())
+	addr := fmt.Sprintf(":%s", cfg.Port)
 
 	fmt.Println("Listening on", addr)
 
@@ -101,3 +255,116 @@ func main() {
 		panic(err)
 	}
 }
+
+// sqliteDataset is the synthetic dataset loaded into an empty SQLite database.
+var sqliteDataset = dataset.SyntheticParams{Films: 1000, Actors: 200, Seed: 1}
+
+// newCache returns the film cache, in process memory only unless the remote
+// cache is enabled.
+func newCache(cfg *config.Config, logger *log.Writer) (*redis.Cache, error) {
+	codec, err := redis.CodecByName(cfg.Cache.Codec)
+	if err != nil {
+		return nil, err
+	}
+
+	compression, err := redis.ParseCompression(cfg.Cache.Compression)
+	if err != nil {
+		return nil, err
+	}
+
+	var cache *redis.Cache
+
+	if cfg.Cache.Remote {
+		tlsConfig, err := cfg.Redis.TLSConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		cache, err = redis.NewCache(&redis.ClientParams{
+			Mode:             cfg.Redis.Mode,
+			Addrs:            cfg.Redis.Addrs,
+			Host:             cfg.Redis.Host,
+			Port:             cfg.Redis.Port,
+			MasterName:       cfg.Redis.MasterName,
+			Username:         cfg.Redis.Username,
+			Password:         cfg.Redis.Password,
+			SentinelPassword: cfg.Redis.SentinelPassword,
+			DB:               cfg.Redis.DB,
+			TLSConfig:        tlsConfig,
+			PoolSize:         cfg.Redis.PoolSize,
+			MinIdleConns:     cfg.Redis.MinIdleConns,
+			PoolTimeout:      cfg.Redis.PoolTimeout,
+			DialTimeout:      cfg.Redis.DialTimeout,
+			ReadTimeout:      cfg.Redis.ReadTimeout,
+			WriteTimeout:     cfg.Redis.WriteTimeout,
+			LocalCacheSize:   cfg.Cache.LocalSize,
+			LocalCacheTTL:    cfg.Cache.LocalTTL,
+			KeyPrefix:        cfg.Redis.KeyPrefix,
+			Logger:           logger,
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		cache = redis.NewLocalCache(cfg.Cache.LocalSize, cfg.Cache.LocalTTL)
+	}
+
+	cache.Codec = codec
+	cache.Compression = compression
+	cache.CompressionThreshold = cfg.Cache.CompressionThreshold
+
+	return cache, nil
+}
+
+// startCacheWarmer starts warming the film cache, unless no films or film
+// lists are preloaded, and records the accesses to films for it.
+func startCacheWarmer(cfg *config.Config, filmCache *redis.FilmService, logger *log.Writer) *redis.Warmer {
+	if cfg.Cache.WarmFilms == 0 && cfg.Cache.WarmLists == 0 {
+		return nil
+	}
+
+	filmCache.RecordAccesses = true
+
+	warmer := &redis.Warmer{
+		FilmService: filmCache,
+		Films:       cfg.Cache.WarmFilms,
+		Lists:       cfg.Cache.WarmLists,
+		Interval:    cfg.Cache.WarmInterval,
+		Rate:        cfg.Cache.WarmRate,
+		Logger:      logger,
+	}
+
+	go func() {
+		if err := warmer.Run(context.Background()); err != nil {
+			logger.Error(err)
+		}
+	}()
+
+	return warmer
+}
+
+// openSQLite opens a SQLite database, creating its tables and loading a
+// synthetic dataset when it has no films.
+func openSQLite(ctx context.Context, path string) (*sqlite.DB, error) {
+	db, err := sqlite.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Migrate(ctx); err != nil {
+		return nil, err
+	}
+
+	empty, err := db.Empty(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if empty {
+		if err := db.Seed(ctx, dataset.Synthetic(sqliteDataset)); err != nil {
+			return nil, err
+		}
+	}
+
+	return db, nil
+}
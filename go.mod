@@ -1,23 +1,29 @@
 module github.com/nickmro/sakila-service-film
 
-go 1.13
+go 1.16
 
 require (
 	github.com/InVisionApp/go-health v2.1.0+incompatible
 	github.com/InVisionApp/go-health/v2 v2.1.2
+	github.com/alicebob/miniredis/v2 v2.14.3
 	github.com/go-chi/chi v1.5.4
 	github.com/go-redis/cache/v8 v8.4.0
 	github.com/go-redis/redis/v8 v8.8.2
 	github.com/go-sql-driver/mysql v1.5.0
+	github.com/gorilla/websocket v1.4.2
 	github.com/graph-gophers/dataloader v5.0.0+incompatible
 	github.com/graphql-go/graphql v0.7.9
 	github.com/graphql-go/handler v0.2.3
+	github.com/klauspost/compress v1.11.4
 	github.com/kr/pretty v0.2.1 // indirect
+	github.com/mattn/go-sqlite3 v1.14.7
 	github.com/nickmro/mrqb v0.0.0-20210528231604-00d597342c49 // indirect
 	github.com/onsi/ginkgo v1.15.0
 	github.com/onsi/gomega v1.10.5
 	github.com/opentracing/opentracing-go v1.2.0 // indirect
 	github.com/spf13/viper v1.7.1
+	github.com/vmihailenco/msgpack/v5 v5.1.0
 	go.uber.org/zap v1.10.0
+	google.golang.org/protobuf v1.25.0
 	gopkg.in/DATA-DOG/go-sqlmock.v1 v1.3.0 // indirect
 )
@@ -1,5 +1,7 @@
 package sakila
 
+import "context"
+
 // Actor is a sakila film actor.
 type Actor struct {
 	ActorID int `json:"actorId"`
@@ -10,3 +12,8 @@ type FilmActor struct {
 	Actor
 	FilmID int
 }
+
+// ActorService defines the interface for a film service that finds actors.
+type ActorService interface {
+	GetActors(ctx context.Context, actorIDs ...int) ([]*Actor, error)
+}
@@ -1,2 +1,183 @@
 // Package config provides configuration services and methods.
 package config
+
+import "time"
+
+// Config is the service configuration. Each field tagged with a config key is
+// a setting, read from the environment variable named after its key, such as
+// MYSQL_HOST for mysql.host, and from the flag of the same name, such as
+// -mysql-host. Settings tagged reloadable are applied by a Watcher without
+// restarting the service.
+type Config struct {
+	Port             string        `config:"port" usage:"The server port"`
+	Logger           string        `config:"logger" usage:"The logger type (TEST, DEVELOPMENT, PRODUCTION)"`
+	LogLevel         string        `config:"log_level" usage:"The log level (debug, info, warn, error)" reloadable:"true"`
+	SupportedLocales []string      `config:"supported_locales" usage:"The comma separated locales (empty disables)"`
+	WatchInterval    time.Duration `config:"watch_interval" usage:"The config file polling interval (0 disables)"`
+
+	Database DatabaseConfig `config:"database"`
+	MySQL    MySQLConfig    `config:"mysql"`
+	SQLite   SQLiteConfig   `config:"sqlite"`
+	Redis    RedisConfig    `config:"redis"`
+	Cache    CacheConfig    `config:"cache"`
+
+	sources map[string]Source
+}
+
+// DatabaseConfig is the film database configuration.
+type DatabaseConfig struct {
+	Driver string `config:"driver" usage:"The film database (mysql, sqlite)"`
+}
+
+// MySQLConfig is the MySQL database configuration.
+type MySQLConfig struct {
+	Host               string        `config:"host" usage:"The database host (required for mysql without a socket)"`
+	Port               string        `config:"port" usage:"The database port"`
+	Socket             string        `config:"socket" usage:"The database unix socket, used instead of the host"`
+	Name               string        `config:"name" usage:"The database name"`
+	User               string        `config:"user" usage:"The database user"`
+	Password           string        `config:"password" usage:"The database password" secret:"true"`
+	TLSMode            string        `config:"tls_mode" usage:"The TLS mode (disabled, preferred, required, skip-verify)"`
+	TLSCA              string        `config:"tls_ca" usage:"The PEM CA certificate file verifying the server"`
+	TLSCert            string        `config:"tls_cert" usage:"The PEM client certificate file"`
+	TLSKey             string        `config:"tls_key" usage:"The PEM client key file"`
+	TLSServerName      string        `config:"tls_server_name" usage:"The server name verified (default: the host)"`
+	Charset            string        `config:"charset" usage:"The connection character set"`
+	Collation          string        `config:"collation" usage:"The connection collation (default: the charset default)"`
+	Loc                string        `config:"loc" usage:"The time zone of the parsed times, such as UTC or Local"`
+	Timeout            time.Duration `config:"timeout" usage:"The connection timeout"`
+	ReadTimeout        time.Duration `config:"read_timeout" usage:"The I/O read timeout (0 is none)"`
+	WriteTimeout       time.Duration `config:"write_timeout" usage:"The I/O write timeout (0 is none)"`
+	ChangeFeedInterval time.Duration `config:"change_feed_interval" usage:"The change polling interval (0 disables)"`
+	MaxOpenConns       int           `config:"max_open_conns" usage:"The maximum open connections (0 is unlimited)"`
+	MaxIdleConns       int           `config:"max_idle_conns" usage:"The maximum idle connections"`
+	ConnMaxLifetime    time.Duration `config:"conn_max_lifetime" usage:"The maximum connection lifetime (0 is unlimited)"`
+}
+
+// SQLiteConfig is the SQLite database configuration.
+type SQLiteConfig struct {
+	Path string `config:"path" usage:"The SQLite database file (:memory: for in-memory)"`
+}
+
+// RedisConfig is the Redis cache configuration.
+type RedisConfig struct {
+	Mode             string        `config:"mode" usage:"The deployment mode (standalone, sentinel, cluster)"`
+	Host             string        `config:"host" usage:"The cache host (required without addrs)"`
+	Port             int           `config:"port" usage:"The cache port"`
+	Addrs            []string      `config:"addrs" usage:"The comma separated server, sentinel or cluster node addresses"`
+	MasterName       string        `config:"master_name" usage:"The sentinel master name (required for sentinel)"`
+	Username         string        `config:"username" usage:"The cache ACL username"`
+	Password         string        `config:"password" usage:"The cache password" secret:"true"`
+	SentinelPassword string        `config:"sentinel_password" usage:"The sentinel password" secret:"true"`
+	DB               int           `config:"db" usage:"The cache database (0 for cluster)"`
+	KeyPrefix        string        `config:"key_prefix" usage:"The cache key prefix"`
+	TLSMode          string        `config:"tls_mode" usage:"The TLS mode (disabled, required, skip-verify)"`
+	TLSCA            string        `config:"tls_ca" usage:"The PEM CA certificate file verifying the server"`
+	TLSCert          string        `config:"tls_cert" usage:"The PEM client certificate file"`
+	TLSKey           string        `config:"tls_key" usage:"The PEM client key file"`
+	TLSServerName    string        `config:"tls_server_name" usage:"The server name verified (default: the host)"`
+	PoolSize         int           `config:"pool_size" usage:"The maximum connections per node (0 is 10 per CPU)"`
+	MinIdleConns     int           `config:"min_idle_conns" usage:"The minimum idle connections per node"`
+	PoolTimeout      time.Duration `config:"pool_timeout" usage:"The connection wait timeout (0 is the read timeout + 1s)"`
+	DialTimeout      time.Duration `config:"dial_timeout" usage:"The connection timeout"`
+	ReadTimeout      time.Duration `config:"read_timeout" usage:"The command read timeout"`
+	WriteTimeout     time.Duration `config:"write_timeout" usage:"The command write timeout"`
+}
+
+// CacheConfig is the film cache configuration. Films are cached in process
+// memory, in front of Redis.
+type CacheConfig struct {
+	Remote       bool          `config:"remote" usage:"Whether films are cached in Redis"`
+	TTL          time.Duration `config:"ttl" usage:"The Redis cache TTL" reloadable:"true"`
+	FilmTTL      time.Duration `config:"film_ttl" usage:"The Redis cache TTL of single films (0 is the TTL)"`
+	FilmsTTL     time.Duration `config:"films_ttl" usage:"The Redis cache TTL of film lists (0 is the TTL)"`
+	ActorsTTL    time.Duration `config:"actors_ttl" usage:"The Redis cache TTL of film actor lists (0 is the TTL)"`
+	TTLJitter    time.Duration `config:"ttl_jitter" usage:"The maximum random duration added to the Redis cache TTLs"`
+	StaleTTL     time.Duration `config:"stale_ttl" usage:"How long expired items are served while refreshed (0 disables)"`
+	NegativeTTL  time.Duration `config:"negative_ttl" usage:"How long not found films are cached (0 disables)"`
+	LocalSize    int           `config:"local_size" usage:"The number of items kept in process memory (0 disables)"`
+	LocalTTL     time.Duration `config:"local_ttl" usage:"The in-process cache TTL"`
+	WarmFilms    int           `config:"warm_films" usage:"The number of most requested films preloaded"`
+	WarmLists    int           `config:"warm_lists" usage:"The number of most requested film list pages preloaded"`
+	WarmInterval time.Duration `config:"warm_interval" usage:"The cache warm-up interval (0 warms on startup only)"`
+	WarmRate     int           `config:"warm_rate" usage:"The maximum items preloaded per second (0 is unlimited)"`
+	WarmReady    bool          `config:"warm_ready" usage:"Whether the service is ready only once the cache is warmed"`
+
+	Codec                string `config:"codec" usage:"The cached value encoding (msgpack, json, protobuf)"`
+	Compression          string `config:"compression" usage:"The cached value compression (none, s2, zstd)"`
+	CompressionThreshold int    `config:"compression_threshold" usage:"The size in bytes from which values are compressed"`
+}
+
+// The supported database drivers.
+const (
+	DatabaseDriverMySQL  = "mysql"
+	DatabaseDriverSQLite = "sqlite"
+)
+
+// The Redis deployment modes.
+const (
+	RedisModeStandalone = "standalone"
+	RedisModeSentinel   = "sentinel"
+	RedisModeCluster    = "cluster"
+)
+
+// The cached value codecs.
+const (
+	CacheCodecMsgpack  = "msgpack"
+	CacheCodecJSON     = "json"
+	CacheCodecProtobuf = "protobuf"
+)
+
+// The cached value compressions.
+const (
+	CacheCompressionNone = "none"
+	CacheCompressionS2   = "s2"
+	CacheCompressionZstd = "zstd"
+)
+
+// Default returns the default configuration.
+func Default() *Config {
+	return &Config{
+		Port:             "3000",
+		Logger:           "DEVELOPMENT",
+		SupportedLocales: []string{},
+		Database: DatabaseConfig{
+			Driver: DatabaseDriverMySQL,
+		},
+		MySQL: MySQLConfig{
+			Port:               "3306",
+			Name:               "sakila",
+			TLSMode:            TLSModeDisabled,
+			Charset:            "utf8mb4",
+			Loc:                "UTC",
+			Timeout:            time.Second * 10,
+			ChangeFeedInterval: time.Second * 5,
+			MaxIdleConns:       2,
+		},
+		SQLite: SQLiteConfig{
+			Path: "sakila.db",
+		},
+		Redis: RedisConfig{
+			Mode:         RedisModeStandalone,
+			Port:         6379,
+			TLSMode:      TLSModeDisabled,
+			DialTimeout:  time.Second * 5,
+			ReadTimeout:  time.Second * 3,
+			WriteTimeout: time.Second * 3,
+		},
+		Cache: CacheConfig{
+			Remote:               true,
+			TTL:                  time.Minute * 5,
+			TTLJitter:            time.Second * 30,
+			StaleTTL:             time.Minute,
+			NegativeTTL:          time.Second * 30,
+			LocalSize:            10000,
+			LocalTTL:             time.Minute * 5,
+			WarmInterval:         time.Minute * 10,
+			WarmRate:             20,
+			Codec:                CacheCodecMsgpack,
+			Compression:          CacheCompressionS2,
+			CompressionThreshold: 1024,
+		},
+	}
+}
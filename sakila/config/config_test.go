@@ -0,0 +1,267 @@
+package config_test
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nickmro/sakila-service-film/sakila/config"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Config", func() {
+	var dir string
+	var wd string
+	var fs *flag.FlagSet
+	var setenv func(key, value string)
+	var envKeys []string
+
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		Expect(ioutil.WriteFile(path, []byte(content), 0600)).To(Succeed())
+
+		return path
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		wd, err = os.Getwd()
+		Expect(err).ToNot(HaveOccurred())
+
+		dir, err = ioutil.TempDir("", "config")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.Chdir(dir)).To(Succeed())
+
+		fs = flag.NewFlagSet("test", flag.ContinueOnError)
+		config.RegisterFlags(fs)
+
+		envKeys = nil
+		setenv = func(key, value string) {
+			envKeys = append(envKeys, key)
+			Expect(os.Setenv(key, value)).To(Succeed())
+		}
+
+		setenv("MYSQL_HOST", "localhost")
+		setenv("REDIS_HOST", "localhost")
+	})
+
+	AfterEach(func() {
+		for _, key := range envKeys {
+			Expect(os.Unsetenv(key)).To(Succeed())
+		}
+
+		Expect(os.Chdir(wd)).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	Describe("Load", func() {
+		It("returns the defaults", func() {
+			cfg, err := config.Load(fs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.Port).To(Equal("3000"))
+			Expect(cfg.Database.Driver).To(Equal(config.DatabaseDriverMySQL))
+			Expect(cfg.MySQL.Port).To(Equal("3306"))
+			Expect(cfg.Redis.Port).To(Equal(6379))
+			Expect(cfg.Cache.TTL).To(Equal(time.Minute * 5))
+			Expect(cfg.Source("port")).To(Equal(config.SourceDefault))
+		})
+
+		It("reads a YAML config file", func() {
+			setenv("CONFIG_FILE", writeFile("config.yaml", `
+port: 4000
+supported_locales: [fr, de]
+mysql:
+  change_feed_interval: 10s
+redis:
+  pool_size: 20
+`))
+
+			cfg, err := config.Load(fs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.Port).To(Equal("4000"))
+			Expect(cfg.SupportedLocales).To(Equal([]string{"fr", "de"}))
+			Expect(cfg.MySQL.ChangeFeedInterval).To(Equal(time.Second * 10))
+			Expect(cfg.Redis.PoolSize).To(Equal(20))
+			Expect(cfg.Source("redis.pool_size")).To(Equal(config.SourceFile))
+		})
+
+		It("reads a TOML config file named by the flag", func() {
+			file := writeFile("config.toml", `
+[cache]
+ttl = "1m"
+`)
+			Expect(fs.Parse([]string{"-config", file})).To(Succeed())
+
+			cfg, err := config.Load(fs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.Cache.TTL).To(Equal(time.Minute))
+		})
+
+		It("layers the environment over the file, and the flags over the environment", func() {
+			setenv("CONFIG_FILE", writeFile("config.yaml", "port: 4000\nlogger: production\n"))
+			setenv("PORT", "5000")
+			setenv("SUPPORTED_LOCALES", "fr, de")
+			Expect(fs.Parse([]string{"-port", "6000"})).To(Succeed())
+
+			cfg, err := config.Load(fs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.Port).To(Equal("6000"))
+			Expect(cfg.Source("port")).To(Equal(config.SourceFlag))
+			Expect(cfg.Logger).To(Equal("PRODUCTION"))
+			Expect(cfg.SupportedLocales).To(Equal([]string{"fr", "de"}))
+			Expect(cfg.Source("supported_locales")).To(Equal(config.SourceEnv))
+		})
+
+		It("reads a .env file under the environment", func() {
+			writeFile(".env", "PORT=4000\nREDIS_PORT=6380\n")
+			setenv("PORT", "5000")
+
+			cfg, err := config.Load(fs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.Port).To(Equal("5000"))
+			Expect(cfg.Redis.Port).To(Equal(6380))
+			Expect(cfg.Source("redis.port")).To(Equal(config.SourceDotEnv))
+		})
+
+		It("reads secrets from the files named by _FILE variables", func() {
+			setenv("MYSQL_PASSWORD_FILE", writeFile("mysql_password", "secret\n"))
+
+			cfg, err := config.Load(fs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.MySQL.Password).To(Equal("secret"))
+			Expect(cfg.Source("mysql.password")).To(Equal(config.SourceSecretFile))
+		})
+
+		It("reports every invalid setting", func() {
+			Expect(os.Unsetenv("REDIS_HOST")).To(Succeed())
+			setenv("CONFIG_FILE", writeFile("config.yaml", "cache:\n  size: 10\n"))
+			setenv("DATABASE_DRIVER", "postgres")
+			setenv("REDIS_PORT", "redis")
+			Expect(fs.Parse([]string{"-cache-ttl", "0s"})).To(Succeed())
+
+			cfg, err := config.Load(fs)
+			Expect(cfg).To(BeNil())
+			Expect(err).To(MatchError(config.ErrorInvalid))
+			Expect(err).To(MatchError(config.ErrorMissing))
+			Expect(err).To(MatchError(config.ErrorUnknown))
+
+			var errs config.Errors
+			Expect(err).To(BeAssignableToTypeOf(errs))
+			Expect(err.(config.Errors)).To(HaveLen(5))
+		})
+	})
+
+	Describe("Redis", func() {
+		It("reads the sentinel settings", func() {
+			setenv("REDIS_MODE", "sentinel")
+			setenv("REDIS_ADDRS", "sentinel-1:26379, sentinel-2:26379")
+			setenv("REDIS_MASTER_NAME", "sakila")
+			setenv("REDIS_USERNAME", "film")
+
+			cfg, err := config.Load(fs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.Redis.Mode).To(Equal(config.RedisModeSentinel))
+			Expect(cfg.Redis.Addrs).To(Equal([]string{"sentinel-1:26379", "sentinel-2:26379"}))
+			Expect(cfg.Redis.MasterName).To(Equal("sakila"))
+			Expect(cfg.Redis.Username).To(Equal("film"))
+		})
+
+		It("requires the master name of a sentinel deployment", func() {
+			setenv("REDIS_MODE", "sentinel")
+
+			_, err := config.Load(fs)
+			Expect(err).To(MatchError(config.ErrorMissing))
+			Expect(err.Error()).To(ContainSubstring("redis.master_name"))
+		})
+
+		It("rejects a database index for a cluster", func() {
+			setenv("REDIS_MODE", "cluster")
+			setenv("REDIS_DB", "1")
+
+			_, err := config.Load(fs)
+			Expect(err).To(MatchError(config.ErrorInvalid))
+			Expect(err.Error()).To(ContainSubstring("redis.db"))
+		})
+
+		It("returns no TLS config when TLS is disabled", func() {
+			cfg, err := config.Load(fs)
+			Expect(err).ToNot(HaveOccurred())
+
+			tlsConfig, err := cfg.Redis.TLSConfig()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tlsConfig).To(BeNil())
+		})
+
+		It("returns the TLS config", func() {
+			setenv("REDIS_TLS_MODE", "skip-verify")
+
+			cfg, err := config.Load(fs)
+			Expect(err).ToNot(HaveOccurred())
+
+			tlsConfig, err := cfg.Redis.TLSConfig()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tlsConfig.InsecureSkipVerify).To(BeTrue())
+		})
+
+		It("rejects the preferred TLS mode", func() {
+			setenv("REDIS_TLS_MODE", "preferred")
+
+			_, err := config.Load(fs)
+			Expect(err).To(MatchError(config.ErrorInvalid))
+			Expect(err.Error()).To(ContainSubstring("redis.tls_mode"))
+		})
+	})
+
+	Describe("Cache", func() {
+		It("reads the per-operation TTLs", func() {
+			setenv("CACHE_FILM_TTL", "1h")
+			setenv("CACHE_TTL_JITTER", "0s")
+
+			cfg, err := config.Load(fs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.Cache.FilmTTL).To(Equal(time.Hour))
+			Expect(cfg.Cache.FilmsTTL).To(BeZero())
+			Expect(cfg.Cache.TTLJitter).To(BeZero())
+		})
+
+		It("does not require Redis without the remote cache", func() {
+			Expect(os.Unsetenv("REDIS_HOST")).To(Succeed())
+			setenv("CACHE_REMOTE", "false")
+
+			cfg, err := config.Load(fs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.Cache.Remote).To(BeFalse())
+		})
+
+		It("requires the local cache without the remote cache", func() {
+			setenv("CACHE_REMOTE", "false")
+			setenv("CACHE_LOCAL_SIZE", "0")
+
+			_, err := config.Load(fs)
+			Expect(err).To(MatchError(config.ErrorInvalid))
+			Expect(err.Error()).To(ContainSubstring("cache.local_size"))
+		})
+	})
+
+	Describe("Print", func() {
+		It("prints the settings with the secrets redacted", func() {
+			setenv("REDIS_PASSWORD", "secret")
+
+			cfg, err := config.Load(fs)
+			Expect(err).ToNot(HaveOccurred())
+
+			var b bytes.Buffer
+			Expect(cfg.Print(&b)).To(Succeed())
+			Expect(b.String()).To(MatchRegexp(`redis\.password\s+\[REDACTED\]\s+env`))
+			Expect(b.String()).To(MatchRegexp(`mysql\.host\s+localhost\s+env`))
+			Expect(b.String()).To(MatchRegexp(`port\s+3000\s+default`))
+			Expect(b.String()).ToNot(ContainSubstring("secret"))
+		})
+	})
+})
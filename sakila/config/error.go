@@ -1,12 +1,47 @@
 package config
 
+import (
+	"errors"
+	"strings"
+)
+
 // Error is a config error.
 type Error string
 
 // ErrorMissing returns a missing config error.
 const ErrorMissing = Error("missing")
 
+// ErrorInvalid returns an invalid config error.
+const ErrorInvalid = Error("invalid")
+
+// ErrorUnknown returns an unknown config error.
+const ErrorUnknown = Error("unknown")
+
 // Error returns the error as a string.
 func (e Error) Error() string {
 	return string(e)
 }
+
+// Errors are the errors of every invalid setting of a config.
+type Errors []error
+
+// Error returns the errors as a string.
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i := range e {
+		msgs[i] = e[i].Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Is reports whether any of the errors matches the target.
+func (e Errors) Is(target error) bool {
+	for i := range e {
+		if errors.Is(e[i], target) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,240 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Source is where a setting value is read from.
+type Source string
+
+// The setting sources, in increasing precedence.
+const (
+	SourceDefault    = Source("default")
+	SourceFile       = Source("file")
+	SourceDotEnv     = Source(".env")
+	SourceEnv        = Source("env")
+	SourceSecretFile = Source("secret file")
+	SourceFlag       = Source("flag")
+)
+
+const (
+	fileFlagName     = "config"
+	fileEnvName      = "CONFIG_FILE"
+	dotEnvFileName   = ".env"
+	secretFileSuffix = "_FILE"
+)
+
+// RegisterFlags defines the -config flag, naming the config file, and a flag
+// for each setting on the flag set.
+func RegisterFlags(fs *flag.FlagSet) {
+	defaults := Default()
+
+	fs.String(fileFlagName, "", "The YAML or TOML config file (default $"+fileEnvName+")")
+
+	for _, s := range settings {
+		fs.String(s.flagName(), s.format(defaults), s.usage)
+	}
+}
+
+// Load returns the config layered from, in increasing precedence, the
+// defaults, the config file, a .env file in the working directory, the
+// environment and the flags set on the flag set, which may be nil.
+//
+// A setting can also be read from the file named by its environment variable
+// suffixed with _FILE, such as MYSQL_PASSWORD_FILE, to load secrets. Every
+// invalid setting is reported in a single Errors error.
+func Load(fs *flag.FlagSet) (*Config, error) { //nolint:gocyclo
+	c := Default()
+	c.sources = map[string]Source{}
+
+	errs := Errors{}
+	flags := setFlags(fs)
+
+	env, err := readEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	file, ok := flags[fileFlagName]
+	if !ok {
+		file, _, _ = env.lookup(fileEnvName)
+	}
+
+	if file != "" {
+		values, err := readFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			s, ok := lookupSetting(key)
+			if !ok {
+				errs = append(errs, fmt.Errorf("%w: %s", ErrorUnknown, key))
+				continue
+			}
+
+			errs = c.set(s, values[key], SourceFile, errs)
+		}
+	}
+
+	for _, s := range settings {
+		value, source, err := env.lookupSetting(s)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: %s: %v", ErrorInvalid, s.key, err))
+		} else if source != "" {
+			errs = c.set(s, value, source, errs)
+		}
+	}
+
+	for _, s := range settings {
+		if value, ok := flags[s.flagName()]; ok {
+			errs = c.set(s, value, SourceFlag, errs)
+		}
+	}
+
+	c.normalize()
+
+	errs = append(errs, c.validate()...)
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return c, nil
+}
+
+// Source returns the source of the setting with the key.
+func (c *Config) Source(key string) Source {
+	if source, ok := c.sources[key]; ok {
+		return source
+	}
+
+	return SourceDefault
+}
+
+// set sets the setting of the config, appending its error to the errors.
+func (c *Config) set(s *setting, value interface{}, source Source, errs Errors) Errors {
+	if err := s.set(c, value); err != nil {
+		return append(errs, fmt.Errorf("%w: %s: %v", ErrorInvalid, s.key, err))
+	}
+
+	c.sources[s.key] = source
+
+	return errs
+}
+
+func (c *Config) normalize() {
+	c.Logger = strings.ToUpper(c.Logger)
+	c.LogLevel = strings.ToLower(c.LogLevel)
+	c.Database.Driver = strings.ToLower(c.Database.Driver)
+	c.MySQL.TLSMode = strings.ToLower(c.MySQL.TLSMode)
+	c.Redis.Mode = strings.ToLower(c.Redis.Mode)
+	c.Redis.TLSMode = strings.ToLower(c.Redis.TLSMode)
+	c.Cache.Codec = strings.ToLower(c.Cache.Codec)
+	c.Cache.Compression = strings.ToLower(c.Cache.Compression)
+}
+
+// setFlags returns the values of the flags set on the flag set.
+func setFlags(fs *flag.FlagSet) map[string]string {
+	values := map[string]string{}
+
+	if fs != nil {
+		fs.Visit(func(f *flag.Flag) {
+			values[f.Name] = f.Value.String()
+		})
+	}
+
+	return values
+}
+
+// readFile returns the settings of a config file, in any format supported by
+// viper, such as YAML or TOML, by key.
+func readFile(file string) (map[string]interface{}, error) {
+	v := viper.New()
+	v.SetConfigFile(file)
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+	for _, key := range v.AllKeys() {
+		values[key] = v.Get(key)
+	}
+
+	return values, nil
+}
+
+// environment is the process environment, over the variables of a .env file.
+type environment struct {
+	dotEnv map[string]string
+}
+
+func readEnvironment() (*environment, error) {
+	env := &environment{dotEnv: map[string]string{}}
+
+	if _, err := os.Stat(dotEnvFileName); os.IsNotExist(err) {
+		return env, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(dotEnvFileName)
+	v.SetConfigType("env")
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	for _, key := range v.AllKeys() {
+		env.dotEnv[strings.ToUpper(key)] = v.GetString(key)
+	}
+
+	return env, nil
+}
+
+// lookup returns the value and source of the variable. Empty variables are
+// treated as unset.
+func (env *environment) lookup(name string) (string, Source, bool) {
+	if value := os.Getenv(name); value != "" {
+		return value, SourceEnv, true
+	}
+
+	if value := env.dotEnv[name]; value != "" {
+		return value, SourceDotEnv, true
+	}
+
+	return "", "", false
+}
+
+// lookupSetting returns the value and source of the setting variable, or of
+// the file named by its _FILE variable. The source is empty when neither is
+// set.
+func (env *environment) lookupSetting(s *setting) (string, Source, error) {
+	value, source, ok := env.lookup(s.envName())
+
+	file, fileSource, fileOK := env.lookup(s.envName() + secretFileSuffix)
+	if fileOK && (!ok || fileSource == SourceEnv && source == SourceDotEnv) {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", "", err
+		}
+
+		return strings.TrimRight(string(b), "\r\n"), SourceSecretFile, nil
+	}
+
+	return value, source, nil
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlTLSConfigName is the name of the TLS config registered with the MySQL
+// driver for custom certificates.
+const mysqlTLSConfigName = "sakila"
+
+// DSN returns the MySQL data source name. A TLS config loading the custom CA
+// and client certificates is registered with the driver.
+func (c MySQLConfig) DSN() (string, error) {
+	cfg := mysql.NewConfig()
+	cfg.User = c.User
+	cfg.Passwd = c.Password
+	cfg.DBName = c.Name
+	cfg.ParseTime = true
+	cfg.Collation = c.Collation
+	cfg.Timeout = c.Timeout
+	cfg.ReadTimeout = c.ReadTimeout
+	cfg.WriteTimeout = c.WriteTimeout
+
+	if c.Socket != "" {
+		cfg.Net = "unix"
+		cfg.Addr = c.Socket
+	} else {
+		cfg.Net = "tcp"
+		cfg.Addr = net.JoinHostPort(c.Host, c.Port)
+	}
+
+	if c.Charset != "" {
+		cfg.Params = map[string]string{"charset": c.Charset}
+	}
+
+	if c.Loc != "" {
+		loc, err := time.LoadLocation(c.Loc)
+		if err != nil {
+			return "", err
+		}
+
+		cfg.Loc = loc
+	}
+
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return "", err
+	}
+
+	cfg.TLSConfig = tlsConfig
+
+	return cfg.FormatDSN(), nil
+}
+
+// tlsConfig returns the TLS config name of the DSN, registering the custom
+// TLS config when certificates are set.
+func (c MySQLConfig) tlsConfig() (string, error) {
+	settings := c.tlsSettings()
+
+	switch c.TLSMode {
+	case "", TLSModeDisabled:
+		return "false", nil
+	case TLSModePreferred:
+		if settings.custom() {
+			return "", errors.New("custom certificates require the required or skip-verify TLS mode")
+		}
+
+		return "preferred", nil
+	case TLSModeRequired, TLSModeSkipVerify:
+		if !settings.custom() {
+			if c.TLSMode == TLSModeSkipVerify {
+				return "skip-verify", nil
+			}
+
+			return "true", nil
+		}
+	default:
+		return "", fmt.Errorf("unknown TLS mode %q", c.TLSMode)
+	}
+
+	config, err := settings.load()
+	if err != nil {
+		return "", err
+	}
+
+	if config.ServerName == "" {
+		config.ServerName = c.Host
+	}
+
+	if err := mysql.RegisterTLSConfig(mysqlTLSConfigName, config); err != nil {
+		return "", err
+	}
+
+	return mysqlTLSConfigName, nil
+}
+
+func (c MySQLConfig) tlsSettings() tlsSettings {
+	return tlsSettings{
+		prefix:     "mysql.",
+		mode:       c.TLSMode,
+		ca:         c.TLSCA,
+		cert:       c.TLSCert,
+		key:        c.TLSKey,
+		serverName: c.TLSServerName,
+	}
+}
@@ -0,0 +1,102 @@
+package config_test
+
+import (
+	"time"
+
+	"github.com/nickmro/sakila-service-film/sakila/config"
+
+	"github.com/go-sql-driver/mysql"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MySQLConfig", func() {
+	var c config.MySQLConfig
+
+	parseDSN := func() *mysql.Config {
+		dsn, err := c.DSN()
+		Expect(err).ToNot(HaveOccurred())
+
+		cfg, err := mysql.ParseDSN(dsn)
+		Expect(err).ToNot(HaveOccurred())
+
+		return cfg
+	}
+
+	BeforeEach(func() {
+		c = config.Default().MySQL
+		c.Host = "localhost"
+	})
+
+	Describe("DSN", func() {
+		It("connects over TCP", func() {
+			cfg := parseDSN()
+			Expect(cfg.Net).To(Equal("tcp"))
+			Expect(cfg.Addr).To(Equal("localhost:3306"))
+			Expect(cfg.DBName).To(Equal("sakila"))
+			Expect(cfg.ParseTime).To(BeTrue())
+			Expect(cfg.TLSConfig).To(Equal("false"))
+		})
+
+		It("keeps a user without a password", func() {
+			c.User = "sakila"
+
+			cfg := parseDSN()
+			Expect(cfg.User).To(Equal("sakila"))
+			Expect(cfg.Passwd).To(BeEmpty())
+		})
+
+		It("keeps a password with separators", func() {
+			c.User = "sakila"
+			c.Password = "p@ss/w:rd?"
+
+			cfg := parseDSN()
+			Expect(cfg.User).To(Equal("sakila"))
+			Expect(cfg.Passwd).To(Equal("p@ss/w:rd?"))
+			Expect(cfg.Addr).To(Equal("localhost:3306"))
+		})
+
+		It("connects over a unix socket", func() {
+			c.Socket = "/var/run/mysqld/mysqld.sock"
+
+			cfg := parseDSN()
+			Expect(cfg.Net).To(Equal("unix"))
+			Expect(cfg.Addr).To(Equal("/var/run/mysqld/mysqld.sock"))
+		})
+
+		It("sets the driver options", func() {
+			c.Charset = "utf8"
+			c.Collation = "utf8_general_ci"
+			c.Loc = "Europe/Paris"
+			c.ReadTimeout = time.Second * 30
+			c.WriteTimeout = time.Second * 20
+
+			cfg := parseDSN()
+			Expect(cfg.Params).To(HaveKeyWithValue("charset", "utf8"))
+			Expect(cfg.Collation).To(Equal("utf8_general_ci"))
+			Expect(cfg.Loc.String()).To(Equal("Europe/Paris"))
+			Expect(cfg.Timeout).To(Equal(time.Second * 10))
+			Expect(cfg.ReadTimeout).To(Equal(time.Second * 30))
+			Expect(cfg.WriteTimeout).To(Equal(time.Second * 20))
+		})
+
+		It("requires TLS", func() {
+			c.TLSMode = config.TLSModeRequired
+			Expect(parseDSN().TLSConfig).To(Equal("true"))
+		})
+
+		It("prefers TLS", func() {
+			c.TLSMode = config.TLSModePreferred
+			Expect(parseDSN().TLSConfig).To(Equal("preferred"))
+		})
+
+		It("returns an error for a missing CA file", func() {
+			c.TLSMode = config.TLSModeRequired
+			c.TLSCA = "missing.pem"
+
+			_, err := c.DSN()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
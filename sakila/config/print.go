@@ -0,0 +1,24 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// redacted replaces the values of secret settings when printed.
+const redacted = "[REDACTED]"
+
+// Print writes the settings of the config with their sources, redacting the
+// secrets.
+func (c *Config) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+
+	for _, s := range settings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", s.key, s.display(c), c.Source(s.key))
+	}
+
+	return tw.Flush()
+}
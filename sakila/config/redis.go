@@ -0,0 +1,24 @@
+package config
+
+import "crypto/tls"
+
+// TLSConfig returns the TLS config of the Redis connections, or nil when TLS
+// is disabled.
+func (c RedisConfig) TLSConfig() (*tls.Config, error) {
+	if c.TLSMode == "" || c.TLSMode == TLSModeDisabled {
+		return nil, nil
+	}
+
+	return c.tlsSettings().load()
+}
+
+func (c RedisConfig) tlsSettings() tlsSettings {
+	return tlsSettings{
+		prefix:     "redis.",
+		mode:       c.TLSMode,
+		ca:         c.TLSCA,
+		cert:       c.TLSCert,
+		key:        c.TLSKey,
+		serverName: c.TLSServerName,
+	}
+}
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// setting is a configuration setting, a field of the Config struct.
+type setting struct {
+	key        string
+	usage      string
+	secret     bool
+	reloadable bool
+	index      []int
+}
+
+// settings are the settings of the Config struct, in field order.
+var settings = structSettings(reflect.TypeOf(Config{}), "", nil)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func structSettings(t reflect.Type, prefix string, index []int) []*setting {
+	s := []*setting{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, ok := field.Tag.Lookup("config")
+		if !ok {
+			continue
+		}
+
+		key := prefix + name
+		fieldIndex := append(append([]int{}, index...), i)
+
+		if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			s = append(s, structSettings(field.Type, key+".", fieldIndex)...)
+			continue
+		}
+
+		s = append(s, &setting{
+			key:        key,
+			usage:      field.Tag.Get("usage"),
+			secret:     field.Tag.Get("secret") == "true",
+			reloadable: field.Tag.Get("reloadable") == "true",
+			index:      fieldIndex,
+		})
+	}
+
+	return s
+}
+
+// lookupSetting returns the setting with the key.
+func lookupSetting(key string) (*setting, bool) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, true
+		}
+	}
+
+	return nil, false
+}
+
+// envName returns the environment variable of the setting, such as
+// MYSQL_HOST for mysql.host.
+func (s *setting) envName() string {
+	return strings.ToUpper(strings.ReplaceAll(s.key, ".", "_"))
+}
+
+// flagName returns the flag of the setting, such as mysql-host for
+// mysql.host.
+func (s *setting) flagName() string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(s.key)
+}
+
+// field returns the field of the setting in the config.
+func (s *setting) field(c *Config) reflect.Value {
+	return reflect.ValueOf(c).Elem().FieldByIndex(s.index)
+}
+
+// format returns the setting value of the config as a string.
+func (s *setting) format(c *Config) string {
+	field := s.field(c)
+
+	switch {
+	case field.Kind() == reflect.Slice:
+		return strings.Join(field.Interface().([]string), ",")
+	case field.Type() == durationType:
+		return field.Interface().(time.Duration).String()
+	case field.Kind() == reflect.Int:
+		return strconv.Itoa(int(field.Int()))
+	case field.Kind() == reflect.Bool:
+		return strconv.FormatBool(field.Bool())
+	default:
+		return field.String()
+	}
+}
+
+// display returns the setting value of the config to display, redacted for
+// secrets.
+func (s *setting) display(c *Config) string {
+	value := s.format(c)
+	if s.secret && value != "" {
+		return redacted
+	}
+
+	return value
+}
+
+// copy copies the setting value of the config from another config.
+func (s *setting) copy(c, from *Config) {
+	s.field(c).Set(s.field(from))
+}
+
+// set sets the setting of the config to a value read from a file, the
+// environment or a flag.
+func (s *setting) set(c *Config, value interface{}) error {
+	field := s.field(c)
+
+	switch {
+	case field.Kind() == reflect.Slice:
+		values, err := stringsValue(value)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(values))
+	case field.Type() == durationType:
+		d, err := durationValue(value)
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(int64(d))
+	case field.Kind() == reflect.Int:
+		i, err := intValue(value)
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(int64(i))
+	case field.Kind() == reflect.Bool:
+		b, err := boolValue(value)
+		if err != nil {
+			return err
+		}
+
+		field.SetBool(b)
+	default:
+		field.SetString(strings.TrimSpace(fmt.Sprint(value)))
+	}
+
+	return nil
+}
+
+// stringsValue returns a list, or a comma separated string, as strings.
+func stringsValue(value interface{}) ([]string, error) {
+	var items []string
+
+	switch value := value.(type) {
+	case string:
+		items = strings.Split(value, ",")
+	case []interface{}:
+		for _, item := range value {
+			items = append(items, fmt.Sprint(item))
+		}
+	case []string:
+		items = value
+	default:
+		return nil, fmt.Errorf("expected a list, got %v", value)
+	}
+
+	values := []string{}
+
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+
+	return values, nil
+}
+
+// durationValue returns a duration string, such as 5s, as a duration.
+func durationValue(value interface{}) (time.Duration, error) {
+	s, ok := value.(string)
+	if !ok {
+		return 0, fmt.Errorf("expected a duration such as 5s, got %v", value)
+	}
+
+	return time.ParseDuration(strings.TrimSpace(s))
+}
+
+func boolValue(value interface{}) (bool, error) {
+	switch value := value.(type) {
+	case bool:
+		return value, nil
+	case string:
+		if b, err := strconv.ParseBool(strings.TrimSpace(value)); err == nil {
+			return b, nil
+		}
+	}
+
+	return false, fmt.Errorf("expected a boolean, got %v", value)
+}
+
+func intValue(value interface{}) (int, error) {
+	switch value := value.(type) {
+	case int:
+		return value, nil
+	case int64:
+		return int(value), nil
+	case float64:
+		if value == float64(int(value)) {
+			return int(value), nil
+		}
+	case string:
+		if i, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("expected an integer, got %v", value)
+}
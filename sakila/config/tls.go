@@ -0,0 +1,86 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// The TLS modes. The preferred mode, falling back to an unencrypted
+// connection, is only supported by MySQL.
+const (
+	TLSModeDisabled   = "disabled"
+	TLSModePreferred  = "preferred"
+	TLSModeRequired   = "required"
+	TLSModeSkipVerify = "skip-verify"
+)
+
+// tlsSettings are the TLS settings of a connection.
+type tlsSettings struct {
+	prefix     string
+	mode       string
+	ca         string
+	cert       string
+	key        string
+	serverName string
+}
+
+// custom reports whether a CA, client certificate or server name is set.
+func (t tlsSettings) custom() bool {
+	return t.ca != "" || t.cert != "" || t.key != "" || t.serverName != ""
+}
+
+// load returns a TLS config verifying the server with the PEM CA certificate
+// file, or the system CAs when empty, and presenting the PEM client
+// certificate and key files when set.
+func (t tlsSettings) load() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         t.serverName,
+		InsecureSkipVerify: t.mode == TLSModeSkipVerify, //nolint:gosec
+	}
+
+	if t.ca != "" {
+		pem, err := ioutil.ReadFile(t.ca)
+		if err != nil {
+			return nil, err
+		}
+
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in %s", t.ca)
+		}
+	}
+
+	if t.cert != "" || t.key != "" {
+		pair, err := tls.LoadX509KeyPair(t.cert, t.key)
+		if err != nil {
+			return nil, err
+		}
+
+		config.Certificates = []tls.Certificate{pair}
+	}
+
+	return config, nil
+}
+
+// validate validates the TLS settings against the supported modes.
+func (t tlsSettings) validate(invalid func(key, format string, args ...interface{}), modes ...string) {
+	supported := false
+
+	for _, mode := range modes {
+		supported = supported || t.mode == mode
+	}
+
+	switch {
+	case !supported:
+		invalid(t.prefix+"tls_mode", "must be one of %s", strings.Join(modes, ", "))
+	case t.custom() && t.mode != TLSModeRequired && t.mode != TLSModeSkipVerify:
+		invalid(t.prefix+"tls_mode", "must be %s or %s with custom certificates", TLSModeRequired, TLSModeSkipVerify)
+	}
+
+	if (t.cert == "") != (t.key == "") {
+		invalid(t.prefix+"tls_cert", "must be set with %stls_key", t.prefix)
+	}
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nickmro/sakila-service-film/sakila/log"
+)
+
+// Validate returns an Errors error listing every invalid setting, or nil when
+// the config is valid.
+func (c *Config) Validate() error {
+	if errs := c.validate(); len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (c *Config) validate() Errors { //nolint:gocyclo
+	errs := Errors{}
+
+	invalid := func(key, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%w: %s: %s", ErrorInvalid, key, fmt.Sprintf(format, args...)))
+	}
+
+	required := func(key, value string) bool {
+		if value == "" {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrorMissing, key))
+			return false
+		}
+
+		return true
+	}
+
+	port := func(key, value string) {
+		if required(key, value) {
+			if n, err := strconv.Atoi(value); err != nil || n < 1 || n > 65535 {
+				invalid(key, "%q is not a port", value)
+			}
+		}
+	}
+
+	positive := func(key string, n int) {
+		if n < 0 {
+			invalid(key, "must not be negative")
+		}
+	}
+
+	duration := func(key string, d time.Duration) {
+		positive(key, int(d))
+	}
+
+	port("port", c.Port)
+
+	switch log.Environment(c.Logger) {
+	case log.EnvironmentTest, log.EnvironmentDevelopment, log.EnvironmentProduction:
+	default:
+		invalid("logger", "must be one of %s, %s, %s",
+			log.EnvironmentTest, log.EnvironmentDevelopment, log.EnvironmentProduction)
+	}
+
+	switch c.LogLevel {
+	case "", "debug", "info", "warn", "error":
+	default:
+		invalid("log_level", "must be one of debug, info, warn, error")
+	}
+
+	duration("watch_interval", c.WatchInterval)
+
+	switch c.Database.Driver {
+	case DatabaseDriverMySQL:
+		if c.MySQL.Socket == "" {
+			required("mysql.host", c.MySQL.Host)
+			port("mysql.port", c.MySQL.Port)
+		}
+
+		required("mysql.name", c.MySQL.Name)
+		c.MySQL.tlsSettings().validate(invalid, TLSModeDisabled, TLSModePreferred, TLSModeRequired, TLSModeSkipVerify)
+
+		if _, err := time.LoadLocation(c.MySQL.Loc); err != nil {
+			invalid("mysql.loc", "%v", err)
+		}
+	case DatabaseDriverSQLite:
+		required("sqlite.path", c.SQLite.Path)
+	default:
+		invalid("database.driver", "must be one of %s, %s", DatabaseDriverMySQL, DatabaseDriverSQLite)
+	}
+
+	duration("mysql.timeout", c.MySQL.Timeout)
+	duration("mysql.read_timeout", c.MySQL.ReadTimeout)
+	duration("mysql.write_timeout", c.MySQL.WriteTimeout)
+	duration("mysql.change_feed_interval", c.MySQL.ChangeFeedInterval)
+	positive("mysql.max_open_conns", c.MySQL.MaxOpenConns)
+	positive("mysql.max_idle_conns", c.MySQL.MaxIdleConns)
+	duration("mysql.conn_max_lifetime", c.MySQL.ConnMaxLifetime)
+
+	if len(c.Redis.Addrs) == 0 && c.Cache.Remote {
+		required("redis.host", c.Redis.Host)
+		port("redis.port", strconv.Itoa(c.Redis.Port))
+	}
+
+	switch c.Redis.Mode {
+	case RedisModeStandalone:
+	case RedisModeSentinel:
+		required("redis.master_name", c.Redis.MasterName)
+	case RedisModeCluster:
+		if c.Redis.DB != 0 {
+			invalid("redis.db", "must be 0 for cluster")
+		}
+	default:
+		invalid("redis.mode", "must be one of %s, %s, %s", RedisModeStandalone, RedisModeSentinel, RedisModeCluster)
+	}
+
+	c.Redis.tlsSettings().validate(invalid, TLSModeDisabled, TLSModeRequired, TLSModeSkipVerify)
+
+	positive("redis.db", c.Redis.DB)
+	positive("redis.pool_size", c.Redis.PoolSize)
+	positive("redis.min_idle_conns", c.Redis.MinIdleConns)
+	duration("redis.pool_timeout", c.Redis.PoolTimeout)
+	duration("redis.dial_timeout", c.Redis.DialTimeout)
+	duration("redis.read_timeout", c.Redis.ReadTimeout)
+	duration("redis.write_timeout", c.Redis.WriteTimeout)
+
+	if c.Cache.TTL < time.Second {
+		invalid("cache.ttl", "must be at least 1s")
+	}
+
+	duration("cache.film_ttl", c.Cache.FilmTTL)
+	duration("cache.films_ttl", c.Cache.FilmsTTL)
+	duration("cache.actors_ttl", c.Cache.ActorsTTL)
+	duration("cache.ttl_jitter", c.Cache.TTLJitter)
+	duration("cache.stale_ttl", c.Cache.StaleTTL)
+	duration("cache.negative_ttl", c.Cache.NegativeTTL)
+	positive("cache.local_size", c.Cache.LocalSize)
+
+	if c.Cache.LocalSize == 0 && !c.Cache.Remote {
+		invalid("cache.local_size", "must be positive when the Redis cache is disabled")
+	}
+
+	if c.Cache.LocalTTL < time.Second {
+		invalid("cache.local_ttl", "must be at least 1s")
+	}
+
+	positive("cache.warm_films", c.Cache.WarmFilms)
+	positive("cache.warm_lists", c.Cache.WarmLists)
+	duration("cache.warm_interval", c.Cache.WarmInterval)
+	positive("cache.warm_rate", c.Cache.WarmRate)
+
+	switch c.Cache.Codec {
+	case CacheCodecMsgpack, CacheCodecJSON, CacheCodecProtobuf:
+	default:
+		invalid("cache.codec", "must be one of %s, %s, %s", CacheCodecMsgpack, CacheCodecJSON, CacheCodecProtobuf)
+	}
+
+	switch c.Cache.Compression {
+	case CacheCompressionNone, CacheCompressionS2, CacheCompressionZstd:
+	default:
+		invalid("cache.compression", "must be one of %s, %s, %s",
+			CacheCompressionNone, CacheCompressionS2, CacheCompressionZstd)
+	}
+
+	positive("cache.compression_threshold", c.Cache.CompressionThreshold)
+
+	return errs
+}
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+)
+
+// Watcher reloads the config when the process receives SIGHUP, or when the
+// config file changes if the Interval is positive, and applies the changes
+// of the reloadable settings by calling OnReload. Changes of the other
+// settings are rejected until the service restarts.
+type Watcher struct {
+	Config   *Config
+	FlagSet  *flag.FlagSet
+	Interval time.Duration
+	OnReload func(c *Config)
+	Logger   sakila.Logger
+
+	mu      sync.Mutex
+	modTime time.Time
+}
+
+// Run reloads the config on SIGHUP, and polls the config file for changes,
+// until the context is done.
+func (w *Watcher) Run(ctx context.Context) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	defer signal.Stop(signals)
+
+	var poll <-chan time.Time
+
+	if w.Interval > 0 {
+		w.modTime, _ = w.fileModTime()
+
+		ticker := time.NewTicker(w.Interval)
+		defer ticker.Stop()
+
+		poll = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-signals:
+			w.logInfo("config: reloading on SIGHUP")
+			w.reload()
+		case <-poll:
+			if modTime, ok := w.fileModTime(); ok && !modTime.Equal(w.modTime) {
+				w.modTime = modTime
+				w.logInfo("config: reloading changed file")
+				w.reload()
+			}
+		}
+	}
+}
+
+// Current returns the current config.
+func (w *Watcher) Current() *Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.Config
+}
+
+// Reload loads the config again, and applies the changes of the reloadable
+// settings. It returns the changed settings, including the rejected ones.
+func (w *Watcher) Reload() ([]Change, error) {
+	next, err := Load(w.FlagSet)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+
+	current := w.Config
+	changes := current.Diff(next)
+
+	reloaded := *current
+	reloaded.sources = map[string]Source{}
+
+	for key, source := range current.sources {
+		reloaded.sources[key] = source
+	}
+
+	applied := false
+
+	for _, change := range changes {
+		if change.Reloadable {
+			s, _ := lookupSetting(change.Key)
+			s.copy(&reloaded, next)
+			reloaded.sources[s.key] = next.Source(s.key)
+			applied = true
+		}
+	}
+
+	if applied {
+		w.Config = &reloaded
+	}
+
+	w.mu.Unlock()
+
+	if applied && w.OnReload != nil {
+		w.OnReload(&reloaded)
+	}
+
+	return changes, nil
+}
+
+func (w *Watcher) reload() {
+	changes, err := w.Reload()
+	if err != nil {
+		w.logError(fmt.Errorf("config: reload: %w", err))
+		return
+	}
+
+	if len(changes) == 0 {
+		w.logInfo("config: unchanged")
+	}
+
+	for _, change := range changes {
+		if change.Reloadable {
+			w.logInfo("config: reloaded", change)
+		} else {
+			w.logInfo("config: rejected", change, "(requires a restart)")
+		}
+	}
+}
+
+// fileModTime returns the modification time of the config file, if any.
+func (w *Watcher) fileModTime() (time.Time, bool) {
+	file := ""
+
+	if w.FlagSet != nil {
+		if f := w.FlagSet.Lookup(fileFlagName); f != nil {
+			file = f.Value.String()
+		}
+	}
+
+	if file == "" {
+		file = os.Getenv(fileEnvName)
+	}
+
+	if file == "" {
+		return time.Time{}, false
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return info.ModTime(), true
+}
+
+func (w *Watcher) logInfo(args ...interface{}) {
+	if logger := w.Logger; logger != nil {
+		logger.Info(args...)
+	}
+}
+
+func (w *Watcher) logError(err error) {
+	if logger := w.Logger; logger != nil {
+		logger.Error(err)
+	}
+}
+
+// Change is the change of a setting between two configs.
+type Change struct {
+	Key        string
+	From       string
+	To         string
+	Reloadable bool
+}
+
+// String returns the change, with secrets redacted.
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %q -> %q", c.Key, c.From, c.To)
+}
+
+// Diff returns the settings changed in the other config, with secrets
+// redacted.
+func (c *Config) Diff(other *Config) []Change {
+	changes := []Change{}
+
+	for _, s := range settings {
+		if s.format(c) != s.format(other) {
+			changes = append(changes, Change{
+				Key:        s.key,
+				From:       s.display(c),
+				To:         s.display(other),
+				Reloadable: s.reloadable,
+			})
+		}
+	}
+
+	return changes
+}
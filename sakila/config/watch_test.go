@@ -0,0 +1,93 @@
+package config_test
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nickmro/sakila-service-film/sakila/config"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Watcher", func() {
+	var file string
+	var watcher *config.Watcher
+	var reloaded *config.Config
+
+	writeConfig := func(content string) {
+		Expect(ioutil.WriteFile(file, []byte(content), 0600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		dir, err := ioutil.TempDir("", "config")
+		Expect(err).ToNot(HaveOccurred())
+
+		file = filepath.Join(dir, "config.yaml")
+		writeConfig("mysql:\n  host: localhost\nredis:\n  host: localhost\n  password: secret\n")
+
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		config.RegisterFlags(fs)
+		Expect(fs.Parse([]string{"-config", file})).To(Succeed())
+
+		cfg, err := config.Load(fs)
+		Expect(err).ToNot(HaveOccurred())
+
+		reloaded = nil
+		watcher = &config.Watcher{
+			Config:  cfg,
+			FlagSet: fs,
+			OnReload: func(c *config.Config) {
+				reloaded = c
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(filepath.Dir(file))).To(Succeed())
+	})
+
+	Describe("Reload", func() {
+		It("applies the changes of the reloadable settings", func() {
+			writeConfig("log_level: warn\nmysql:\n  host: localhost\nredis:\n  host: localhost\n  password: secret\n" +
+				"cache:\n  ttl: 1m\n")
+
+			changes, err := watcher.Reload()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(ConsistOf(
+				config.Change{Key: "log_level", From: "", To: "warn", Reloadable: true},
+				config.Change{Key: "cache.ttl", From: "5m0s", To: "1m0s", Reloadable: true},
+			))
+
+			Expect(reloaded).ToNot(BeNil())
+			Expect(reloaded.Cache.TTL).To(Equal(time.Minute))
+			Expect(reloaded.LogLevel).To(Equal("warn"))
+			Expect(watcher.Current()).To(Equal(reloaded))
+		})
+
+		It("rejects the changes of the other settings", func() {
+			writeConfig("mysql:\n  host: db\nredis:\n  host: localhost\n  password: changed\n")
+
+			changes, err := watcher.Reload()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(ConsistOf(
+				config.Change{Key: "mysql.host", From: "localhost", To: "db"},
+				config.Change{Key: "redis.password", From: "[REDACTED]", To: "[REDACTED]"},
+			))
+
+			Expect(reloaded).To(BeNil())
+			Expect(watcher.Current().MySQL.Host).To(Equal("localhost"))
+		})
+
+		It("keeps the current config when the file is invalid", func() {
+			writeConfig("cache:\n  ttl: never\n")
+
+			_, err := watcher.Reload()
+			Expect(err).To(MatchError(config.ErrorInvalid))
+			Expect(reloaded).To(BeNil())
+		})
+	})
+})
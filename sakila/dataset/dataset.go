@@ -0,0 +1,185 @@
+// Package dataset generates Sakila datasets for the film service tables.
+package dataset
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+)
+
+// Table is the rows of a database table.
+type Table struct {
+	Name    string
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// SyntheticParams are the parameters of a generated dataset.
+type SyntheticParams struct {
+	Films  int
+	Actors int
+	Seed   int64
+}
+
+// Synthetic returns a generated dataset of the given size, in table insertion
+// order. The same params generate the same dataset.
+func Synthetic(params SyntheticParams) []*Table {
+	random := rand.New(rand.NewSource(params.Seed)) //nolint:gosec
+
+	languages := &Table{Name: "language", Columns: []string{"language_id", "name"}}
+	categories := &Table{Name: "category", Columns: []string{"category_id", "name"}}
+	actors := &Table{Name: "actor", Columns: []string{"actor_id", "first_name", "last_name"}}
+	films := &Table{Name: "film", Columns: []string{
+		"film_id", "title", "description", "release_year", "language_id",
+		"rental_duration", "rental_rate", "length", "replacement_cost", "rating", "special_features",
+	}}
+	filmActors := &Table{Name: "film_actor", Columns: []string{"actor_id", "film_id"}}
+	filmCategories := &Table{Name: "film_category", Columns: []string{"film_id", "category_id"}}
+	inventory := &Table{Name: "inventory", Columns: []string{"film_id", "store_id"}}
+
+	for i, name := range syntheticLanguages {
+		languages.add(i+1, name)
+	}
+
+	for i, name := range syntheticCategories {
+		categories.add(i+1, name)
+	}
+
+	for i := 1; i <= params.Actors; i++ {
+		actors.add(i, pick(random, syntheticFirstNames), pick(random, syntheticLastNames))
+	}
+
+	for i := 1; i <= params.Films; i++ {
+		films.add(
+			i,
+			pick(random, syntheticAdjectives)+" "+pick(random, syntheticNouns),
+			fmt.Sprintf("A %s %s of a %s who must %s",
+				titleCase(pick(random, syntheticAdjectives)),
+				pick(random, syntheticGenres),
+				titleCase(pick(random, syntheticNouns)),
+				pick(random, syntheticActions)),
+			2006,
+			1,
+			3+random.Intn(5),
+			[]string{"0.99", "2.99", "4.99"}[random.Intn(3)],
+			46+random.Intn(140),
+			fmt.Sprintf("%d.99", 9+random.Intn(21)),
+			pick(random, syntheticRatings),
+			syntheticSpecialFeatures(random),
+		)
+
+		for _, actorID := range random.Perm(params.Actors)[:minInt(params.Actors, 1+random.Intn(10))] {
+			filmActors.add(actorID+1, i)
+		}
+
+		filmCategories.add(i, 1+random.Intn(len(syntheticCategories)))
+
+		for copies := random.Intn(8); copies > 0; copies-- {
+			inventory.add(i, 1+random.Intn(2))
+		}
+	}
+
+	return []*Table{languages, categories, actors, films, filmActors, filmCategories, inventory}
+}
+
+// Batches returns the rows of the table in batches of the given size.
+func (t *Table) Batches(size int) [][][]interface{} {
+	batches := [][][]interface{}{}
+
+	for start := 0; start < len(t.Rows); start += size {
+		batches = append(batches, t.Rows[start:minInt(start+size, len(t.Rows))])
+	}
+
+	return batches
+}
+
+// InsertQuery returns a multi-row INSERT statement for the given rows, with
+// the values as arguments.
+func (t *Table) InsertQuery(rows [][]interface{}) (query string, args []interface{}) {
+	placeholders := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(t.Columns)), ", ") + ")"
+
+	values := make([]string, 0, len(rows))
+	args = make([]interface{}, 0, len(rows)*len(t.Columns))
+
+	for _, row := range rows {
+		values = append(values, placeholders)
+		args = append(args, row...)
+	}
+
+	query = "INSERT INTO " + t.Name + " (" + strings.Join(t.Columns, ", ") + ") VALUES " + strings.Join(values, ", ")
+
+	return query, args
+}
+
+func (t *Table) add(values ...interface{}) {
+	t.Rows = append(t.Rows, values)
+}
+
+var (
+	syntheticLanguages  = []string{"English", "Italian", "Japanese", "Mandarin", "French", "German"}
+	syntheticCategories = []string{
+		"Action", "Animation", "Children", "Classics", "Comedy", "Documentary", "Drama", "Family",
+		"Foreign", "Games", "Horror", "Music", "New", "Sci-Fi", "Sports", "Travel",
+	}
+	syntheticFirstNames = []string{
+		"PENELOPE", "NICK", "ED", "JENNIFER", "JOHNNY", "BETTE", "GRACE", "MATTHEW", "JOE", "CHRISTIAN",
+		"ZERO", "KARL", "UMA", "VIVIEN", "CUBA", "FRED", "HELEN", "DAN", "BOB", "LUCILLE",
+	}
+	syntheticLastNames = []string{
+		"GUINESS", "WAHLBERG", "CHASE", "DAVIS", "LOLLOBRIGIDA", "NICHOLSON", "MOSTEL", "JOHANSSON",
+		"SWANK", "GABLE", "CAGE", "BERRY", "WOOD", "BERGEN", "OLIVIER", "COSTNER", "VOIGHT", "TORN",
+	}
+	syntheticAdjectives = []string{
+		"ACADEMY", "ACE", "ADAPTATION", "AFFAIR", "AFRICAN", "AGENT", "AIRPLANE", "ALABAMA", "ALADDIN",
+		"ALAMO", "ALASKA", "ALI", "ALLEY", "ALONE", "AMADEUS", "AMELIE", "AMERICAN", "AMISTAD",
+	}
+	syntheticNouns = []string{
+		"DINOSAUR", "GOLDFINGER", "HOLES", "PREJUDICE", "EGG", "TRUMAN", "SIERRA", "PHANTOM", "CALENDAR",
+		"FICTION", "CONFIDENTIAL", "FOREVER", "EVOLUTION", "TRAP", "HOLIDAY", "REUNION", "HOOSIERS", "CHARADE",
+	}
+	syntheticGenres = []string{
+		"Drama", "Documentary", "Story", "Saga", "Panorama", "Reflection", "Tale", "Epistle", "Yarn",
+	}
+	syntheticActions = []string{
+		"Battle a Teacher in The Canadian Rockies", "Find a Car in Ancient India",
+		"Chase a Database Administrator in A Shark Tank", "Outgun a Lumberjack in The Gulf of Mexico",
+		"Succumb a Boat in A Manhattan Penthouse", "Redeem a Moose in A Jet Boat",
+	}
+	syntheticRatings = []string{
+		sakila.RatingG, sakila.RatingPG, sakila.RatingPG13, sakila.RatingR, sakila.RatingNC17,
+	}
+	syntheticFeatures = []string{
+		sakila.SpecialFeatureTrailers, sakila.SpecialFeatureCommentaries,
+		sakila.SpecialFeatureDeletedScenes, sakila.SpecialFeatureBehindTheScenes,
+	}
+)
+
+func syntheticSpecialFeatures(random *rand.Rand) string {
+	features := []string{}
+
+	for _, feature := range syntheticFeatures {
+		if random.Intn(2) == 0 {
+			features = append(features, feature)
+		}
+	}
+
+	return strings.Join(features, ",")
+}
+
+func titleCase(word string) string {
+	return word[:1] + strings.ToLower(word[1:])
+}
+
+func pick(random *rand.Rand, values []string) string {
+	return values[random.Intn(len(values))]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
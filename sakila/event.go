@@ -0,0 +1,45 @@
+package sakila
+
+import (
+	"context"
+	"time"
+)
+
+// FilmEventType is the type of a film change event.
+type FilmEventType string
+
+const (
+	// FilmEventCreated is the event type for a created film.
+	FilmEventCreated = FilmEventType("created")
+	// FilmEventUpdated is the event type for an updated film.
+	FilmEventUpdated = FilmEventType("updated")
+	// FilmEventDeleted is the event type for a deleted film.
+	FilmEventDeleted = FilmEventType("deleted")
+	// FilmEventActorsUpdated is the event type for updated film actors.
+	FilmEventActorsUpdated = FilmEventType("actors_updated")
+	// FilmEventCategoriesUpdated is the event type for updated film categories.
+	FilmEventCategoriesUpdated = FilmEventType("categories_updated")
+	// FilmEventInventoryUpdated is the event type for an updated film inventory.
+	FilmEventInventoryUpdated = FilmEventType("inventory_updated")
+	// FilmEventTranslationsUpdated is the event type for updated film
+	// translations.
+	FilmEventTranslationsUpdated = FilmEventType("translations_updated")
+)
+
+// FilmEvent is a film change event.
+type FilmEvent struct {
+	Type   FilmEventType `json:"type"`
+	FilmID int           `json:"filmId"`
+	Time   time.Time     `json:"time"`
+}
+
+// FilmEventPublisher defines the interface for a film event publisher.
+type FilmEventPublisher interface {
+	PublishFilmEvent(ctx context.Context, event *FilmEvent) error
+}
+
+// FilmEventSubscriber defines the interface for a film event subscriber.
+// The returned channel is closed once the context is done.
+type FilmEventSubscriber interface {
+	SubscribeFilmEvents(ctx context.Context) <-chan *FilmEvent
+}
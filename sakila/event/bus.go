@@ -0,0 +1,61 @@
+package event
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+)
+
+// Bus fans film events out to its subscribers.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[chan *sakila.FilmEvent]struct{}
+}
+
+// subscriberBufferSize is the number of events buffered for each subscriber.
+// Events published to a subscriber with a full buffer are dropped.
+const subscriberBufferSize = 64
+
+// NewBus returns a new event bus.
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: map[chan *sakila.FilmEvent]struct{}{},
+	}
+}
+
+// PublishFilmEvent publishes an event to all subscribers.
+func (bus *Bus) PublishFilmEvent(ctx context.Context, event *sakila.FilmEvent) error {
+	bus.mu.RLock()
+	defer bus.mu.RUnlock()
+
+	for ch := range bus.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// SubscribeFilmEvents returns a channel receiving the published events until
+// the context is done.
+func (bus *Bus) SubscribeFilmEvents(ctx context.Context) <-chan *sakila.FilmEvent {
+	ch := make(chan *sakila.FilmEvent, subscriberBufferSize)
+
+	bus.mu.Lock()
+	bus.subscribers[ch] = struct{}{}
+	bus.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		bus.mu.Lock()
+		delete(bus.subscribers, ch)
+		close(ch)
+		bus.mu.Unlock()
+	}()
+
+	return ch
+}
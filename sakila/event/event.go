@@ -0,0 +1,2 @@
+// Package event provides the in-process film change event bus.
+package event
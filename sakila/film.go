@@ -14,20 +14,67 @@ type Film struct {
 	LanguageID         int       `json:"languageId"`
 	OriginalLanguageID *int      `json:"originalLanguageId,omitempty"`
 	RentalDuration     int       `json:"rentalDuration"`
-	RentalRate         float64   `json:"rentalRate"`
+	RentalRate         Money     `json:"rentalRate"`
 	Length             *int      `json:"length,omitempty"`
-	ReplacementCost    float64   `json:"replacementCost"`
+	ReplacementCost    Money     `json:"replacementCost"`
 	Rating             *string   `json:"rating,omitempty"`
 	SpecialFeatures    []string  `json:"specialFeatures,omitempty"`
 	Actors             []*Actor  `json:"actors"`
 	LastUpdate         time.Time `json:"lastUpdate"`
 }
 
-// FilmParams are film query params.
+// Film ratings.
+const (
+	RatingG    = "G"
+	RatingPG   = "PG"
+	RatingPG13 = "PG-13"
+	RatingR    = "R"
+	RatingNC17 = "NC-17"
+)
+
+// Film special features.
+const (
+	SpecialFeatureTrailers        = "Trailers"
+	SpecialFeatureCommentaries    = "Commentaries"
+	SpecialFeatureDeletedScenes   = "Deleted Scenes"
+	SpecialFeatureBehindTheScenes = "Behind the Scenes"
+)
+
+// FilmField is a film field that can be projected.
+type FilmField string
+
+// Film fields. The film ID is always loaded.
+const (
+	FilmFieldID                 FilmField = "filmId"
+	FilmFieldTitle              FilmField = "title"
+	FilmFieldDescription        FilmField = "description"
+	FilmFieldReleaseYear        FilmField = "releaseYear"
+	FilmFieldLanguageID         FilmField = "languageId"
+	FilmFieldOriginalLanguageID FilmField = "originalLanguageId"
+	FilmFieldRentalDuration     FilmField = "rentalDuration"
+	FilmFieldRentalRate         FilmField = "rentalRate"
+	FilmFieldLength             FilmField = "length"
+	FilmFieldReplacementCost    FilmField = "replacementCost"
+	FilmFieldRating             FilmField = "rating"
+	FilmFieldSpecialFeatures    FilmField = "specialFeatures"
+	FilmFieldLastUpdate         FilmField = "lastUpdate"
+)
+
+// FilmParams are film query params. Films match any of the ratings and all of
+// the special features. Fields project the loaded film fields: services may
+// load more fields than requested, and load every field when none are given.
 type FilmParams struct {
-	FilmIDs []int
-	Limit   int
-	Offset  int
+	FilmIDs         []int
+	Ratings         []string
+	SpecialFeatures []string
+	Limit           int
+	Offset          int
+	Fields          []FilmField
+}
+
+// Projected returns whether the params load only some film fields.
+func (p FilmParams) Projected() bool {
+	return len(p.Fields) > 0
 }
 
 // FilmService defines the interface for a film service.
@@ -36,3 +83,14 @@ type FilmService interface {
 	GetFilms(ctx context.Context, params FilmParams) ([]*Film, error)
 	GetFilmActors(ctx context.Context, filmIDs ...int) ([]*FilmActor, error)
 }
+
+// FilmRelations are the relations loaded with films.
+type FilmRelations struct {
+	Actors bool
+}
+
+// FilmRelationService defines the interface for a film service that loads
+// films with their relations in a single query.
+type FilmRelationService interface {
+	GetFilmsWithRelations(ctx context.Context, params FilmParams, relations FilmRelations) ([]*Film, error)
+}
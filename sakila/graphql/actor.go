@@ -10,43 +10,77 @@ import (
 	"github.com/graphql-go/graphql"
 )
 
-// FilmActorsDataLoader loads data for film actors.
-func FilmActorsDataLoader(service sakila.FilmService) *dataloader.Loader {
-	options := []dataloader.Option{
-		dataloader.WithCache(&dataloader.NoCache{}),
+// ActorDataLoader loads data for actors.
+func ActorDataLoader(service sakila.ActorService, options ...dataloader.Option) *dataloader.Loader {
+	options = append([]dataloader.Option{
 		dataloader.WithBatchCapacity(20),
-	}
+	}, options...)
 
 	return dataloader.NewBatchedLoader(func(
 		ctx context.Context,
 		keys dataloader.Keys,
 	) []*dataloader.Result {
-		filmIDs := make([]int, len(keys))
+		actorIDs, err := uniqueIDs(keys)
+		if err != nil {
+			return errorResults(keys, err)
+		}
+
+		loaderMetrics.Add(loaderNameActor+"_fetched", int64(len(actorIDs)))
+
+		actors, err := service.GetActors(ctx, actorIDs...)
+		if err != nil {
+			return errorResults(keys, err)
+		}
+
+		actorsMap := make(map[string]*sakila.Actor, len(actors))
+		for _, actor := range actors {
+			actorsMap[strconv.Itoa(actor.ActorID)] = actor
+		}
+
+		results := make([]*dataloader.Result, len(keys))
 		for i := range keys {
-			id, err := strconv.ParseInt(keys[i].String(), 10, 32)
-			if err != nil {
-				return []*dataloader.Result{{Error: err}}
+			if actor, ok := actorsMap[keys[i].String()]; ok {
+				results[i] = &dataloader.Result{Data: actor}
+			} else {
+				results[i] = &dataloader.Result{Error: sakila.ErrorNotFound}
 			}
-			filmIDs[i] = int(id)
 		}
 
+		return results
+	}, options...)
+}
+
+// FilmActorsDataLoader loads data for film actors.
+func FilmActorsDataLoader(service sakila.FilmService, options ...dataloader.Option) *dataloader.Loader {
+	options = append([]dataloader.Option{
+		dataloader.WithBatchCapacity(20),
+	}, options...)
+
+	return dataloader.NewBatchedLoader(func(
+		ctx context.Context,
+		keys dataloader.Keys,
+	) []*dataloader.Result {
+		filmIDs, err := uniqueIDs(keys)
+		if err != nil {
+			return errorResults(keys, err)
+		}
+
+		loaderMetrics.Add(loaderNameFilmActors+"_fetched", int64(len(filmIDs)))
+
 		actors, err := service.GetFilmActors(ctx, filmIDs...)
 		if err != nil {
-			return []*dataloader.Result{{Error: err}}
+			return errorResults(keys, err)
 		}
 
-		filmsMap := map[int][]*sakila.Actor{}
+		filmsMap := map[string][]*sakila.Actor{}
 		for _, actor := range actors {
-			if actors := filmsMap[actor.FilmID]; actors != nil {
-				filmsMap[actor.FilmID] = append(actors, &actor.Actor)
-			} else {
-				filmsMap[actor.FilmID] = []*sakila.Actor{&actor.Actor}
-			}
+			key := strconv.Itoa(actor.FilmID)
+			filmsMap[key] = append(filmsMap[key], &actor.Actor)
 		}
 
-		results := make([]*dataloader.Result, len(filmIDs))
-		for i := range filmIDs {
-			if actors, ok := filmsMap[filmIDs[i]]; ok {
+		results := make([]*dataloader.Result, len(keys))
+		for i := range keys {
+			if actors, ok := filmsMap[keys[i].String()]; ok {
 				results[i] = &dataloader.Result{Data: actors}
 			} else {
 				results[i] = &dataloader.Result{Data: []*sakila.Actor{}}
@@ -59,12 +93,11 @@ func FilmActorsDataLoader(service sakila.FilmService) *dataloader.Loader {
 
 // FilmActorsResolver returns actors for the given films.
 func FilmActorsResolver(service sakila.FilmService) graphql.FieldResolveFn {
-	loader := FilmActorsDataLoader(service)
+	loaders := contextLoaders(service)
 
 	return func(params graphql.ResolveParams) (interface{}, error) {
 		if film, ok := params.Source.(*sakila.Film); ok {
-			key := strconv.Itoa(film.FilmID)
-			thunk := loader.Load(params.Context, dataloader.StringKey(key))
+			thunk := loaders(params.Context).LoadFilmActors(params.Context, film.FilmID)
 
 			return func() (interface{}, error) {
 				return thunk()
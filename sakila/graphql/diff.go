@@ -0,0 +1,432 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/kinds"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/printer"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// ChangeLevel is the severity of a schema change.
+type ChangeLevel string
+
+const (
+	// ChangeBreaking is a change that breaks existing clients.
+	ChangeBreaking = ChangeLevel("BREAKING")
+	// ChangeDangerous is a change that may change the behavior of existing
+	// clients, such as a new enum value.
+	ChangeDangerous = ChangeLevel("DANGEROUS")
+	// ChangeSafe is a change that existing clients are not affected by.
+	ChangeSafe = ChangeLevel("SAFE")
+)
+
+// changeLevelOrder orders the changes by decreasing severity.
+var changeLevelOrder = map[ChangeLevel]int{
+	ChangeBreaking:  0,
+	ChangeDangerous: 1,
+	ChangeSafe:      2,
+}
+
+// SchemaChange is a change between two versions of a schema.
+type SchemaChange struct {
+	Level       ChangeLevel
+	Path        string
+	Description string
+}
+
+// String returns the change as a string.
+func (c *SchemaChange) String() string {
+	return fmt.Sprintf("%s %s: %s", c.Level, c.Path, c.Description)
+}
+
+// typeDefinition is a type definition parsed from SDL.
+type typeDefinition struct {
+	kind        string
+	keyFields   string
+	interfaces  map[string]bool
+	fields      map[string]*ast.FieldDefinition
+	inputFields map[string]*ast.InputValueDefinition
+	values      map[string]bool
+}
+
+type schemaDiff struct {
+	changes []*SchemaChange
+}
+
+// DiffSDL returns the changes from the old to the new schema definition
+// language, ordered by decreasing severity.
+func DiffSDL(oldSDL, newSDL string) ([]*SchemaChange, error) {
+	oldTypes, err := parseTypeDefinitions(oldSDL)
+	if err != nil {
+		return nil, err
+	}
+
+	newTypes, err := parseTypeDefinitions(newSDL)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &schemaDiff{}
+
+	for _, name := range sortedKeys(oldTypes, newTypes) {
+		oldType, newType := oldTypes[name], newTypes[name]
+
+		switch {
+		case newType == nil:
+			diff.add(ChangeBreaking, name, "Type was removed.")
+		case oldType == nil:
+			diff.add(ChangeSafe, name, "Type was added.")
+		case oldType.kind != newType.kind:
+			diff.add(ChangeBreaking, name, fmt.Sprintf("Type changed from %s to %s.", oldType.kind, newType.kind))
+		default:
+			diff.diffType(name, oldType, newType)
+		}
+	}
+
+	sort.SliceStable(diff.changes, func(i, j int) bool {
+		return changeLevelOrder[diff.changes[i].Level] < changeLevelOrder[diff.changes[j].Level]
+	})
+
+	return diff.changes, nil
+}
+
+// BreakingChanges returns the breaking changes.
+func BreakingChanges(changes []*SchemaChange) []*SchemaChange {
+	breaking := []*SchemaChange{}
+
+	for _, change := range changes {
+		if change.Level == ChangeBreaking {
+			breaking = append(breaking, change)
+		}
+	}
+
+	return breaking
+}
+
+func (diff *schemaDiff) add(level ChangeLevel, path, description string) {
+	diff.changes = append(diff.changes, &SchemaChange{
+		Level:       level,
+		Path:        path,
+		Description: description,
+	})
+}
+
+func (diff *schemaDiff) diffType(name string, oldType, newType *typeDefinition) {
+	if oldType.keyFields != newType.keyFields {
+		diff.add(ChangeBreaking, name, fmt.Sprintf(
+			"Entity key changed from %q to %q.", oldType.keyFields, newType.keyFields,
+		))
+	}
+
+	diff.diffMembers(name, "Interface", oldType.interfaces, newType.interfaces)
+
+	if oldType.kind == kinds.EnumDefinition {
+		diff.diffMembers(name, "Enum value", oldType.values, newType.values)
+	} else {
+		diff.diffMembers(name, "Union member", oldType.values, newType.values)
+	}
+
+	for _, fieldName := range sortedKeys(oldType.fields, newType.fields) {
+		diff.diffField(name+"."+fieldName, oldType.fields[fieldName], newType.fields[fieldName])
+	}
+
+	for _, fieldName := range sortedKeys(oldType.inputFields, newType.inputFields) {
+		diff.diffInputValue(
+			name+"."+fieldName, "Input field", oldType.inputFields[fieldName], newType.inputFields[fieldName],
+		)
+	}
+}
+
+// diffMembers compares the interfaces, enum values or union members of a
+// type. Removals are breaking, and additions are dangerous since existing
+// clients may not handle the new members.
+func (diff *schemaDiff) diffMembers(path, kind string, oldMembers, newMembers map[string]bool) {
+	for _, member := range sortedKeys(oldMembers, newMembers) {
+		if !newMembers[member] {
+			diff.add(ChangeBreaking, path, fmt.Sprintf("%s %s was removed.", kind, member))
+		} else if !oldMembers[member] {
+			diff.add(ChangeDangerous, path, fmt.Sprintf("%s %s was added.", kind, member))
+		}
+	}
+}
+
+func (diff *schemaDiff) diffField(path string, oldField, newField *ast.FieldDefinition) {
+	switch {
+	case newField == nil:
+		diff.add(ChangeBreaking, path, "Field was removed.")
+		return
+	case oldField == nil:
+		diff.add(ChangeSafe, path, "Field was added.")
+		return
+	}
+
+	if oldType, newType := printTypeNode(oldField.Type), printTypeNode(newField.Type); oldType != newType {
+		level := ChangeBreaking
+		if isSafeOutputTypeChange(oldField.Type, newField.Type) {
+			level = ChangeSafe
+		}
+
+		diff.add(level, path, fmt.Sprintf("Field type changed from %s to %s.", oldType, newType))
+	}
+
+	if !isDeprecated(oldField.Directives) && isDeprecated(newField.Directives) {
+		diff.add(ChangeSafe, path, "Field was deprecated.")
+	}
+
+	oldArgs := inputValueMap(oldField.Arguments)
+	newArgs := inputValueMap(newField.Arguments)
+
+	for _, argName := range sortedKeys(oldArgs, newArgs) {
+		diff.diffInputValue(path+"("+argName+")", "Argument", oldArgs[argName], newArgs[argName])
+	}
+}
+
+func (diff *schemaDiff) diffInputValue(path, kind string, oldValue, newValue *ast.InputValueDefinition) {
+	switch {
+	case newValue == nil:
+		diff.add(ChangeBreaking, path, kind+" was removed.")
+		return
+	case oldValue == nil && isRequired(newValue):
+		diff.add(ChangeBreaking, path, "Required "+strings.ToLower(kind)+" was added.")
+		return
+	case oldValue == nil:
+		diff.add(ChangeDangerous, path, "Optional "+strings.ToLower(kind)+" was added.")
+		return
+	}
+
+	if oldType, newType := printTypeNode(oldValue.Type), printTypeNode(newValue.Type); oldType != newType {
+		level := ChangeBreaking
+		if isSafeInputTypeChange(oldValue.Type, newValue.Type) {
+			level = ChangeSafe
+		}
+
+		diff.add(level, path, fmt.Sprintf("%s type changed from %s to %s.", kind, oldType, newType))
+	}
+
+	oldDefault := printValueNode(oldValue.DefaultValue)
+	newDefault := printValueNode(newValue.DefaultValue)
+
+	if oldDefault != newDefault {
+		diff.add(ChangeDangerous, path, fmt.Sprintf("Default value changed from %q to %q.", oldDefault, newDefault))
+	}
+}
+
+// isSafeOutputTypeChange returns whether clients reading the old output type
+// can read the new type, which is the case when it is the same or stricter.
+func isSafeOutputTypeChange(oldType, newType ast.Type) bool {
+	switch oldType := oldType.(type) {
+	case *ast.Named:
+		if newNonNull, ok := newType.(*ast.NonNull); ok {
+			return isSafeOutputTypeChange(oldType, newNonNull.Type)
+		}
+
+		newNamed, ok := newType.(*ast.Named)
+
+		return ok && newNamed.Name.Value == oldType.Name.Value
+	case *ast.List:
+		if newNonNull, ok := newType.(*ast.NonNull); ok {
+			return isSafeOutputTypeChange(oldType, newNonNull.Type)
+		}
+
+		newList, ok := newType.(*ast.List)
+
+		return ok && isSafeOutputTypeChange(oldType.Type, newList.Type)
+	case *ast.NonNull:
+		newNonNull, ok := newType.(*ast.NonNull)
+
+		return ok && isSafeOutputTypeChange(oldType.Type, newNonNull.Type)
+	default:
+		return false
+	}
+}
+
+// isSafeInputTypeChange returns whether values of the old input type are
+// valid for the new type, which is the case when it is the same or looser.
+func isSafeInputTypeChange(oldType, newType ast.Type) bool {
+	switch oldType := oldType.(type) {
+	case *ast.Named:
+		newNamed, ok := newType.(*ast.Named)
+
+		return ok && newNamed.Name.Value == oldType.Name.Value
+	case *ast.List:
+		newList, ok := newType.(*ast.List)
+
+		return ok && isSafeInputTypeChange(oldType.Type, newList.Type)
+	case *ast.NonNull:
+		if newNonNull, ok := newType.(*ast.NonNull); ok {
+			return isSafeInputTypeChange(oldType.Type, newNonNull.Type)
+		}
+
+		return isSafeInputTypeChange(oldType.Type, newType)
+	default:
+		return false
+	}
+}
+
+func parseTypeDefinitions(sdl string) (map[string]*typeDefinition, error) {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(sdl), Name: "SDL"}),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	types := map[string]*typeDefinition{}
+
+	for _, node := range doc.Definitions {
+		if extension, ok := node.(*ast.TypeExtensionDefinition); ok {
+			node = extension.Definition
+		}
+
+		switch definition := node.(type) {
+		case *ast.ObjectDefinition:
+			types[definition.Name.Value] = &typeDefinition{
+				kind:       definition.Kind,
+				keyFields:  keyFields(definition.Directives),
+				interfaces: namedSet(definition.Interfaces),
+				fields:     fieldMap(definition.Fields),
+			}
+		case *ast.InterfaceDefinition:
+			types[definition.Name.Value] = &typeDefinition{
+				kind:   definition.Kind,
+				fields: fieldMap(definition.Fields),
+			}
+		case *ast.UnionDefinition:
+			types[definition.Name.Value] = &typeDefinition{
+				kind:   definition.Kind,
+				values: namedSet(definition.Types),
+			}
+		case *ast.EnumDefinition:
+			values := map[string]bool{}
+			for _, value := range definition.Values {
+				values[value.Name.Value] = true
+			}
+
+			types[definition.Name.Value] = &typeDefinition{
+				kind:   definition.Kind,
+				values: values,
+			}
+		case *ast.InputObjectDefinition:
+			types[definition.Name.Value] = &typeDefinition{
+				kind:        definition.Kind,
+				inputFields: inputValueMap(definition.Fields),
+			}
+		case *ast.ScalarDefinition:
+			types[definition.Name.Value] = &typeDefinition{
+				kind: definition.Kind,
+			}
+		}
+	}
+
+	return types, nil
+}
+
+func keyFields(directives []*ast.Directive) string {
+	for _, directive := range directives {
+		if directive.Name.Value != "key" {
+			continue
+		}
+
+		for _, arg := range directive.Arguments {
+			if arg.Name.Value == "fields" {
+				return printValueNode(arg.Value)
+			}
+		}
+	}
+
+	return ""
+}
+
+func isDeprecated(directives []*ast.Directive) bool {
+	for _, directive := range directives {
+		if directive.Name.Value == "deprecated" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isRequired(value *ast.InputValueDefinition) bool {
+	_, nonNull := value.Type.(*ast.NonNull)
+	return nonNull && value.DefaultValue == nil
+}
+
+func namedSet(named []*ast.Named) map[string]bool {
+	set := make(map[string]bool, len(named))
+	for i := range named {
+		set[named[i].Name.Value] = true
+	}
+
+	return set
+}
+
+func fieldMap(fields []*ast.FieldDefinition) map[string]*ast.FieldDefinition {
+	m := make(map[string]*ast.FieldDefinition, len(fields))
+	for i := range fields {
+		m[fields[i].Name.Value] = fields[i]
+	}
+
+	return m
+}
+
+func inputValueMap(values []*ast.InputValueDefinition) map[string]*ast.InputValueDefinition {
+	m := make(map[string]*ast.InputValueDefinition, len(values))
+	for i := range values {
+		m[values[i].Name.Value] = values[i]
+	}
+
+	return m
+}
+
+func printTypeNode(t ast.Type) string {
+	return fmt.Sprintf("%v", printer.Print(t))
+}
+
+func printValueNode(value ast.Value) string {
+	if value == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%v", printer.Print(value))
+}
+
+// sortedKeys returns the sorted union of the keys of the maps.
+func sortedKeys(maps ...interface{}) []string {
+	set := map[string]bool{}
+
+	for _, m := range maps {
+		switch m := m.(type) {
+		case map[string]*typeDefinition:
+			for key := range m {
+				set[key] = true
+			}
+		case map[string]*ast.FieldDefinition:
+			for key := range m {
+				set[key] = true
+			}
+		case map[string]*ast.InputValueDefinition:
+			for key := range m {
+				set[key] = true
+			}
+		case map[string]bool:
+			for key := range m {
+				set[key] = true
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,63 @@
+package graphql_test
+
+import (
+	"github.com/nickmro/sakila-service-film/sakila/graphql"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DiffSDL", func() {
+	const oldSDL = `
+		enum Rating {
+			G
+			PG
+		}
+
+		type Film @key(fields: "filmId") {
+			filmId: Int
+			title: String
+			rating: Rating
+		}
+
+		type Query {
+			films(limit: Int): [Film]
+		}
+	`
+
+	DescribeTable("classifies the changes",
+		func(newSDL string, level graphql.ChangeLevel, path string) {
+			changes, err := graphql.DiffSDL(oldSDL, newSDL)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(HaveLen(1))
+			Expect(changes[0].Level).To(Equal(level))
+			Expect(changes[0].Path).To(Equal(path))
+		},
+		Entry("removed field", `
+			enum Rating { G PG }
+			type Film @key(fields: "filmId") { filmId: Int rating: Rating }
+			type Query { films(limit: Int): [Film] }
+		`, graphql.ChangeBreaking, "Film.title"),
+		Entry("nullable output field", `
+			enum Rating { G PG }
+			type Film @key(fields: "filmId") { filmId: Int! title: String rating: Rating }
+			type Query { films(limit: Int): [Film] }
+		`, graphql.ChangeSafe, "Film.filmId"),
+		Entry("required argument", `
+			enum Rating { G PG }
+			type Film @key(fields: "filmId") { filmId: Int title: String rating: Rating }
+			type Query { films(limit: Int!): [Film] }
+		`, graphql.ChangeBreaking, "Query.films(limit)"),
+		Entry("added enum value", `
+			enum Rating { G PG R }
+			type Film @key(fields: "filmId") { filmId: Int title: String rating: Rating }
+			type Query { films(limit: Int): [Film] }
+		`, graphql.ChangeDangerous, "Rating"),
+		Entry("changed entity key", `
+			enum Rating { G PG }
+			type Film @key(fields: "title") { filmId: Int title: String rating: Rating }
+			type Query { films(limit: Int): [Film] }
+		`, graphql.ChangeBreaking, "Film"),
+	)
+})
@@ -0,0 +1,223 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// entityType describes an Apollo Federation entity type.
+type entityType struct {
+	keyFields string
+	extended  bool
+}
+
+// entityTypes are the federation entity types by name. Extended types are
+// stubs of entities owned by other services.
+var entityTypes = map[string]entityType{
+	"Actor":    {keyFields: "actorId"},
+	"Film":     {keyFields: "filmId"},
+	"Language": {keyFields: "languageId", extended: true},
+	"Store":    {keyFields: "storeId", extended: true},
+}
+
+// federationTypes are the types added to the schema by Apollo Federation.
+var federationTypes = map[string]bool{
+	"_Any":     true,
+	"_Entity":  true,
+	"_Service": true,
+}
+
+// federationFields are the query fields added to the schema by Apollo
+// Federation.
+var federationFields = map[string]bool{
+	"_entities": true,
+	"_service":  true,
+}
+
+// anyType is the federation scalar for entity representations.
+var anyType = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "_Any",
+	Description: "An entity representation.",
+	Serialize: func(value interface{}) interface{} {
+		return value
+	},
+	ParseValue: func(value interface{}) interface{} {
+		return value
+	},
+	ParseLiteral: func(value ast.Value) interface{} {
+		return literalValue(value)
+	},
+})
+
+// serviceType is the federation type describing the service.
+var serviceType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "_Service",
+	Description: "The federated service.",
+	Fields: graphql.Fields{
+		"sdl": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The service schema definition language.",
+		},
+	},
+})
+
+// ServiceResolver returns the federated service description.
+func ServiceResolver() graphql.FieldResolveFn {
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		return map[string]interface{}{"sdl": printSDL(params.Info.Schema)}, nil
+	}
+}
+
+// EntitiesResolver returns the entities for the given representations.
+func EntitiesResolver(service sakila.FilmService) graphql.FieldResolveFn {
+	loaders := contextLoaders(service)
+
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		representations, _ := params.Args["representations"].([]interface{})
+
+		thunks := make([]func() (interface{}, error), len(representations))
+
+		for i := range representations {
+			thunk, err := entityThunk(params.Context, loaders(params.Context), representations[i])
+			if err != nil {
+				return nil, err
+			}
+
+			thunks[i] = thunk
+		}
+
+		return func() (interface{}, error) {
+			entities := make([]interface{}, len(thunks))
+
+			for i := range thunks {
+				entity, err := thunks[i]()
+				if errors.Is(err, sakila.ErrorNotFound) {
+					continue
+				} else if err != nil {
+					return nil, err
+				}
+
+				entities[i] = entity
+			}
+
+			return entities, nil
+		}, nil
+	}
+}
+
+// ResolveEntityType returns the object type of an entity.
+func ResolveEntityType(filmType, actorType *graphql.Object) graphql.ResolveTypeFn {
+	return func(params graphql.ResolveTypeParams) *graphql.Object {
+		switch params.Value.(type) {
+		case *sakila.Film:
+			return filmType
+		case *sakila.Actor:
+			return actorType
+		default:
+			return nil
+		}
+	}
+}
+
+func entityThunk(
+	ctx context.Context,
+	loaders *Loaders,
+	representation interface{},
+) (func() (interface{}, error), error) {
+	fields, ok := representation.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid entity representation: %v", representation)
+	}
+
+	switch typename := fields["__typename"]; typename {
+	case "Film":
+		filmID, err := intField(fields, "filmId")
+		if err != nil {
+			return nil, err
+		}
+
+		thunk := loaders.LoadFilm(ctx, filmID)
+
+		return func() (interface{}, error) {
+			return thunk()
+		}, nil
+	case "Actor":
+		actorID, err := intField(fields, "actorId")
+		if err != nil {
+			return nil, err
+		}
+
+		if loaders.Actor == nil {
+			return nil, fmt.Errorf("unsupported entity type: %v", typename)
+		}
+
+		thunk := loaders.LoadActor(ctx, actorID)
+
+		return func() (interface{}, error) {
+			return thunk()
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown entity type: %v", typename)
+	}
+}
+
+// intField returns an integer representation field, which is a float when
+// decoded from JSON variables.
+func intField(fields map[string]interface{}, name string) (int, error) {
+	switch value := fields[name].(type) {
+	case int:
+		return value, nil
+	case float64:
+		return int(value), nil
+	case string:
+		return strconv.Atoi(value)
+	default:
+		return 0, fmt.Errorf("invalid entity field %s: %v", name, value)
+	}
+}
+
+func literalValue(value ast.Value) interface{} {
+	switch value := value.(type) {
+	case *ast.ObjectValue:
+		fields := make(map[string]interface{}, len(value.Fields))
+		for _, field := range value.Fields {
+			fields[field.Name.Value] = literalValue(field.Value)
+		}
+
+		return fields
+	case *ast.ListValue:
+		values := make([]interface{}, len(value.Values))
+		for i := range value.Values {
+			values[i] = literalValue(value.Values[i])
+		}
+
+		return values
+	case *ast.IntValue:
+		if i, err := strconv.Atoi(value.Value); err == nil {
+			return i
+		}
+
+		return nil
+	case *ast.FloatValue:
+		if f, err := strconv.ParseFloat(value.Value, 64); err == nil {
+			return f
+		}
+
+		return nil
+	case *ast.StringValue:
+		return value.Value
+	case *ast.BooleanValue:
+		return value.Value
+	case *ast.EnumValue:
+		return value.Value
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,81 @@
+package graphql_test
+
+import (
+	"context"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+	"github.com/nickmro/sakila-service-film/sakila/graphql"
+	"github.com/nickmro/sakila-service-film/sakila/mock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Federation", func() {
+	var schema *graphql.Schema
+	var filmService *mock.FilmService
+
+	BeforeEach(func() {
+		filmService = &mock.FilmService{}
+		s, err := graphql.NewSchema(filmService)
+		if err != nil {
+			panic(err)
+		}
+		schema = s
+	})
+
+	Describe("_service", func() {
+		It("returns the subgraph SDL", func() {
+			b, err := schema.Request(`{ _service { sdl } }`)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(b)).To(ContainSubstring(`type Film implements Node @key(fields: \"filmId\")`))
+			Expect(string(b)).To(ContainSubstring(`type Actor implements Node @key(fields: \"actorId\")`))
+			Expect(string(b)).To(ContainSubstring(`extend type Store @key(fields: \"storeId\")`))
+			Expect(string(b)).ToNot(ContainSubstring(`_entities`))
+		})
+	})
+
+	Describe("_entities", func() {
+		It("loads the films and the actors in a single batch each", func() {
+			var calls, actorCalls int
+
+			filmService.GetFilmsFn = func(ctx context.Context, params sakila.FilmParams) ([]*sakila.Film, error) {
+				calls++
+				Expect(params.FilmIDs).To(ConsistOf(1, 2))
+
+				return []*sakila.Film{{FilmID: 2}, {FilmID: 1}}, nil
+			}
+
+			filmService.GetActorsFn = func(ctx context.Context, actorIDs ...int) ([]*sakila.Actor, error) {
+				actorCalls++
+				Expect(actorIDs).To(ConsistOf(5, 6))
+
+				return []*sakila.Actor{{ActorID: 5}}, nil
+			}
+
+			query := `
+				{
+					_entities(representations: [
+						{__typename: "Film", filmId: 1},
+						{__typename: "Actor", actorId: 5},
+						{__typename: "Film", filmId: 2},
+						{__typename: "Actor", actorId: 6}
+					]) {
+						... on Film {
+							filmId
+						}
+						... on Actor {
+							actorId
+						}
+					}
+				}
+			`
+
+			b, err := schema.Request(query)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(calls).To(Equal(1))
+			Expect(actorCalls).To(Equal(1))
+			Expect(b).To(MatchJSON(`{"_entities":[{"filmId":1},{"actorId":5},{"filmId":2},null]}`))
+		})
+	})
+})
@@ -1,16 +1,142 @@
 package graphql
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
 	"github.com/nickmro/sakila-service-film/sakila"
 
+	"github.com/graph-gophers/dataloader"
 	"github.com/graphql-go/graphql"
 )
 
-// FilmResolver returns the film with the given ID.
+// MaxFilmIDs is the maximum number of film IDs of a films query.
+const MaxFilmIDs = 1000
+
+// FilmDataLoader loads data for films.
+func FilmDataLoader(service sakila.FilmService, options ...dataloader.Option) *dataloader.Loader {
+	options = append([]dataloader.Option{
+		dataloader.WithBatchCapacity(20),
+	}, options...)
+
+	return dataloader.NewBatchedLoader(func(
+		ctx context.Context,
+		keys dataloader.Keys,
+	) []*dataloader.Result {
+		filmIDs, err := uniqueIDs(keys)
+		if err != nil {
+			return errorResults(keys, err)
+		}
+
+		loaderMetrics.Add(loaderNameFilm+"_fetched", int64(len(filmIDs)))
+
+		films, err := loadFilms(ctx, service, filmIDs)
+		if err != nil {
+			return errorResults(keys, err)
+		}
+
+		filmsMap := make(map[string]*sakila.Film, len(films))
+		for _, film := range films {
+			filmsMap[strconv.Itoa(film.FilmID)] = film
+		}
+
+		results := make([]*dataloader.Result, len(keys))
+		for i := range keys {
+			if film, ok := filmsMap[keys[i].String()]; ok {
+				results[i] = &dataloader.Result{Data: film}
+			} else {
+				results[i] = &dataloader.Result{Error: sakila.ErrorNotFound}
+			}
+		}
+
+		return results
+	}, options...)
+}
+
+// FilmWithActorsDataLoader loads data for films along with their actors.
+func FilmWithActorsDataLoader(service sakila.FilmRelationService, options ...dataloader.Option) *dataloader.Loader {
+	options = append([]dataloader.Option{
+		dataloader.WithBatchCapacity(20),
+	}, options...)
+
+	return dataloader.NewBatchedLoader(func(
+		ctx context.Context,
+		keys dataloader.Keys,
+	) []*dataloader.Result {
+		filmIDs, err := uniqueIDs(keys)
+		if err != nil {
+			return errorResults(keys, err)
+		}
+
+		loaderMetrics.Add(loaderNameFilmWithActors+"_fetched", int64(len(filmIDs)))
+
+		films, err := service.GetFilmsWithRelations(
+			ctx,
+			sakila.FilmParams{FilmIDs: filmIDs},
+			sakila.FilmRelations{Actors: true},
+		)
+		if err != nil {
+			return errorResults(keys, err)
+		}
+
+		filmsMap := make(map[string]*sakila.Film, len(films))
+		for _, film := range films {
+			filmsMap[strconv.Itoa(film.FilmID)] = film
+		}
+
+		results := make([]*dataloader.Result, len(keys))
+		for i := range keys {
+			if film, ok := filmsMap[keys[i].String()]; ok {
+				results[i] = &dataloader.Result{Data: film}
+			} else {
+				results[i] = &dataloader.Result{Error: sakila.ErrorNotFound}
+			}
+		}
+
+		return results
+	}, options...)
+}
+
+// FilmResolver returns the film with the given ID. Films requested with their
+// actors are batched with the other films of the operation requested with
+// their actors, and prime the film and film actors loaders.
 func FilmResolver(service sakila.FilmService) graphql.FieldResolveFn {
+	loaders := contextLoaders(service)
+
 	return func(params graphql.ResolveParams) (i interface{}, e error) {
 		if filmID, ok := params.Args["filmId"].(int); ok {
-			return service.GetFilm(params.Context, filmID)
+			operationLoaders := loaders(params.Context)
+			relations := requestedFilmRelations(params.Info)
+
+			if relations.Actors && operationLoaders.FilmWithActors != nil {
+				thunk := operationLoaders.LoadFilmWithActors(params.Context, filmID)
+
+				return func() (interface{}, error) {
+					data, err := thunk()
+					if err != nil {
+						return nil, err
+					}
+
+					film, ok := data.(*sakila.Film)
+					if !ok {
+						return nil, sakila.ErrorNotFound
+					}
+
+					operationLoaders.PrimeFilms(params.Context, []*sakila.Film{film})
+					operationLoaders.PrimeFilmRelations(params.Context, []*sakila.Film{film}, relations)
+
+					return film, nil
+				}, nil
+			}
+
+			thunk := operationLoaders.LoadFilm(params.Context, filmID)
+
+			return func() (interface{}, error) {
+				return thunk()
+			}, nil
 		}
 
 		return nil, nil
@@ -19,9 +145,26 @@ func FilmResolver(service sakila.FilmService) graphql.FieldResolveFn {
 
 // FilmsResolver returns films for the given parameters.
 func FilmsResolver(service sakila.FilmService) graphql.FieldResolveFn {
+	loaders := contextLoaders(service)
+
 	return func(params graphql.ResolveParams) (i interface{}, e error) {
 		filmParams := sakila.FilmParams{}
 
+		if filmIDs, ok := params.Args["filmIds"].([]interface{}); ok {
+			if len(filmIDs) > MaxFilmIDs {
+				return nil, fmt.Errorf("at most %d film IDs can be requested", MaxFilmIDs)
+			}
+
+			for _, filmID := range filmIDs {
+				if id, ok := filmID.(int); ok {
+					filmParams.FilmIDs = append(filmParams.FilmIDs, id)
+				}
+			}
+		}
+
+		filmParams.Ratings = stringArgs(params.Args, "ratings")
+		filmParams.SpecialFeatures = stringArgs(params.Args, "specialFeatures")
+
 		if limit, ok := params.Args["limit"].(int); ok {
 			filmParams.Limit = limit
 		}
@@ -30,6 +173,139 @@ func FilmsResolver(service sakila.FilmService) graphql.FieldResolveFn {
 			filmParams.Offset = offset
 		}
 
+		fields := requestedFilmFields(params.Info)
+
+		idsOnly := len(filmParams.FilmIDs) > 0 && len(filmParams.Ratings) == 0 && len(filmParams.SpecialFeatures) == 0
+		relations := requestedFilmRelations(params.Info)
+
+		if relationService, ok := relationService(service, relations); ok {
+			if !idsOnly {
+				filmParams.Fields = fields
+
+				return loadFilmsWithRelations(params.Context, loaders(params.Context), relationService, filmParams, relations)
+			}
+
+			films, err := loadFilmsWithRelations(
+				params.Context,
+				loaders(params.Context),
+				relationService,
+				sakila.FilmParams{FilmIDs: filmParams.FilmIDs, Fields: fields},
+				relations,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			return pageFilms(sortedFilms(films), filmParams), nil
+		}
+
+		if idsOnly {
+			thunk := loaders(params.Context).LoadFilms(params.Context, filmParams.FilmIDs)
+
+			return func() (interface{}, error) {
+				return pagedFilms(thunk, filmParams)
+			}, nil
+		}
+
+		filmParams.Fields = fields
+
 		return service.GetFilms(params.Context, filmParams)
 	}
 }
+
+// loadFilms fetches the films with the given IDs, using a single film lookup
+// when only one film is requested.
+func loadFilms(ctx context.Context, service sakila.FilmService, filmIDs []int) ([]*sakila.Film, error) {
+	if len(filmIDs) != 1 {
+		return service.GetFilms(ctx, sakila.FilmParams{FilmIDs: filmIDs})
+	}
+
+	film, err := service.GetFilm(ctx, filmIDs[0])
+	if errors.Is(err, sakila.ErrorNotFound) {
+		return []*sakila.Film{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return []*sakila.Film{film}, nil
+}
+
+// loadFilmsWithRelations fetches the films with their relations in a single
+// call, priming the loaders with the results. Projected films are not primed
+// into the film loader, as other fields of the operation may need the fields
+// they lack.
+func loadFilmsWithRelations(
+	ctx context.Context,
+	loaders *Loaders,
+	service sakila.FilmRelationService,
+	params sakila.FilmParams,
+	relations sakila.FilmRelations,
+) ([]*sakila.Film, error) {
+	films, err := service.GetFilmsWithRelations(ctx, params, relations)
+	if err != nil {
+		return nil, err
+	}
+
+	if !params.Projected() {
+		loaders.PrimeFilms(ctx, films)
+	}
+
+	loaders.PrimeFilmRelations(ctx, films, relations)
+
+	return films, nil
+}
+
+// pagedFilms returns the loaded films ordered by ID, skipping films that were
+// not found and applying the limit and offset of the params.
+func pagedFilms(thunk dataloader.ThunkMany, params sakila.FilmParams) ([]*sakila.Film, error) {
+	data, errs := thunk()
+
+	films := make([]*sakila.Film, 0, len(data))
+	seen := make(map[int]bool, len(data))
+
+	for i := range data {
+		if i < len(errs) && errs[i] != nil {
+			if errors.Is(errs[i], sakila.ErrorNotFound) {
+				continue
+			}
+
+			return nil, errs[i]
+		}
+
+		if film, ok := data[i].(*sakila.Film); ok && !seen[film.FilmID] {
+			seen[film.FilmID] = true
+			films = append(films, film)
+		}
+	}
+
+	return pageFilms(sortedFilms(films), params), nil
+}
+
+// sortedFilms returns the films ordered by ID, like the films paged by the
+// film service.
+func sortedFilms(films []*sakila.Film) []*sakila.Film {
+	sorted := append([]*sakila.Film{}, films...)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].FilmID < sorted[j].FilmID
+	})
+
+	return sorted
+}
+
+// pageFilms applies the limit and offset of the params to the films.
+func pageFilms(films []*sakila.Film, params sakila.FilmParams) []*sakila.Film {
+	if offset := params.Offset; offset > 0 {
+		if offset > len(films) {
+			offset = len(films)
+		}
+
+		films = films[offset:]
+	}
+
+	if limit := params.Limit; limit > 0 && limit < len(films) {
+		films = films[:limit]
+	}
+
+	return films
+}
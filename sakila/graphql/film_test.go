@@ -44,11 +44,11 @@ var _ = Describe("Schema", func() {
 					LanguageID:         1,
 					OriginalLanguageID: intP(1),
 					RentalDuration:     6,
-					RentalRate:         0.99,
+					RentalRate:         sakila.Money(99),
 					Length:             intP(86),
-					ReplacementCost:    20.99,
-					Rating:             stringP("PG"),
-					SpecialFeatures:    []string{"Documentary"},
+					ReplacementCost:    sakila.Money(2099),
+					Rating:             stringP(sakila.RatingPG13),
+					SpecialFeatures:    []string{sakila.SpecialFeatureTrailers},
 					LastUpdate:         time.Now(),
 				}, nil
 			}
@@ -107,19 +107,67 @@ var _ = Describe("Schema", func() {
 			Expect(data.Film.OriginalLanguageID).ToNot(BeNil())
 			Expect(*data.Film.OriginalLanguageID).To(Equal(1))
 			Expect(data.Film.RentalDuration).To(Equal(6))
-			Expect(data.Film.RentalRate).To(Equal(0.99))
+			Expect(data.Film.RentalRate).To(Equal(sakila.Money(99)))
 			Expect(data.Film.Length).ToNot(BeNil())
 			Expect(*data.Film.Length).To(Equal(86))
-			Expect(data.Film.ReplacementCost).To(Equal(20.99))
+			Expect(data.Film.ReplacementCost).To(Equal(sakila.Money(2099)))
 			Expect(data.Film.Rating).ToNot(BeNil())
-			Expect(*data.Film.Rating).To(Equal("PG"))
+			Expect(*data.Film.Rating).To(Equal("PG_13"))
 			Expect(data.Film.SpecialFeatures).ToNot(BeNil())
 			Expect(data.Film.SpecialFeatures).To(HaveLen(1))
-			Expect(data.Film.SpecialFeatures[0]).To(Equal("Documentary"))
+			Expect(data.Film.SpecialFeatures[0]).To(Equal("TRAILERS"))
 			Expect(data.Film.LastUpdate).ToNot(BeZero())
 			Expect(data.Film.Actors).To(HaveLen(1))
 			Expect(data.Film.Actors[0].ActorID).To(Equal(1))
 		})
+
+		It("returns exact money amounts", func() {
+			b, err := schema.Request(`{ film(filmId: 1) { rentalRate replacementCost(format: CURRENCY) } }`)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(b).To(MatchJSON(`{"film":{"rentalRate":"0.99","replacementCost":"$20.99"}}`))
+		})
+
+		Context("when the same film is requested more than once", func() {
+			It("loads the film and its actors once", func() {
+				var filmCalls int
+				var actorCalls int
+
+				getFilm := filmService.GetFilmFn
+				filmService.GetFilmFn = func(ctx context.Context, filmID int) (*sakila.Film, error) {
+					filmCalls++
+					return getFilm(ctx, filmID)
+				}
+
+				getFilmActors := filmService.GetFilmActorsFn
+				filmService.GetFilmActorsFn = func(ctx context.Context, filmIDs ...int) ([]*sakila.FilmActor, error) {
+					actorCalls++
+					Expect(filmIDs).To(Equal([]int{1}))
+					return getFilmActors(ctx, filmIDs...)
+				}
+
+				query := `
+					{
+						first: film(filmId: 1) {
+							filmId
+							actors {
+								actorId
+							}
+						}
+						second: film(filmId: 1) {
+							title
+							actors {
+								actorId
+							}
+						}
+					}
+				`
+
+				_, err := schema.Request(query)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(filmCalls).To(Equal(1))
+				Expect(actorCalls).To(Equal(1))
+			})
+		})
 	})
 
 	Describe("films", func() {
@@ -136,11 +184,11 @@ var _ = Describe("Schema", func() {
 						LanguageID:         1,
 						OriginalLanguageID: intP(1),
 						RentalDuration:     6,
-						RentalRate:         0.99,
+						RentalRate:         sakila.Money(99),
 						Length:             intP(86),
-						ReplacementCost:    20.99,
+						ReplacementCost:    sakila.Money(2099),
 						Rating:             stringP("PG"),
-						SpecialFeatures:    []string{"Documentary"},
+						SpecialFeatures:    []string{sakila.SpecialFeatureTrailers},
 						LastUpdate:         time.Now(),
 					},
 				}, nil
@@ -204,15 +252,15 @@ var _ = Describe("Schema", func() {
 			Expect(film.OriginalLanguageID).ToNot(BeNil())
 			Expect(*film.OriginalLanguageID).To(Equal(1))
 			Expect(film.RentalDuration).To(Equal(6))
-			Expect(film.RentalRate).To(Equal(0.99))
+			Expect(film.RentalRate).To(Equal(sakila.Money(99)))
 			Expect(film.Length).ToNot(BeNil())
 			Expect(*film.Length).To(Equal(86))
-			Expect(film.ReplacementCost).To(Equal(20.99))
+			Expect(film.ReplacementCost).To(Equal(sakila.Money(2099)))
 			Expect(film.Rating).ToNot(BeNil())
 			Expect(*film.Rating).To(Equal("PG"))
 			Expect(film.SpecialFeatures).ToNot(BeNil())
 			Expect(film.SpecialFeatures).To(HaveLen(1))
-			Expect(film.SpecialFeatures[0]).To(Equal("Documentary"))
+			Expect(film.SpecialFeatures[0]).To(Equal("TRAILERS"))
 			Expect(film.LastUpdate).ToNot(BeZero())
 			Expect(film.Actors).To(HaveLen(1))
 			Expect(film.Actors[0].ActorID).To(Equal(1))
@@ -244,6 +292,68 @@ var _ = Describe("Schema", func() {
 				Expect(offset).To(Equal(100))
 			})
 		})
+
+		It("projects the requested fields", func() {
+			var filmParams sakila.FilmParams
+
+			filmService.GetFilmsFn = func(ctx context.Context, params sakila.FilmParams) ([]*sakila.Film, error) {
+				filmParams = params
+				return []*sakila.Film{}, nil
+			}
+
+			_, err := schema.Request(`{ films(limit: 10) { filmId title(locale: "fr") rentalRate language { languageId } } }`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filmParams.Fields).To(ConsistOf(
+				sakila.FilmFieldID,
+				sakila.FilmFieldTitle,
+				sakila.FilmFieldRentalRate,
+				sakila.FilmFieldLanguageID,
+			))
+		})
+
+		It("returns the films with the requested IDs ordered by ID", func() {
+			filmService.GetFilmsFn = func(ctx context.Context, params sakila.FilmParams) ([]*sakila.Film, error) {
+				films := []*sakila.Film{}
+				for _, filmID := range params.FilmIDs {
+					films = append(films, &sakila.Film{FilmID: filmID})
+				}
+
+				return films, nil
+			}
+
+			b, err := schema.Request(`{ films(filmIds: [4, 2, 4]) { filmId } }`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(b).To(MatchJSON(`{"films":[{"filmId":2},{"filmId":4}]}`))
+
+			b, err = schema.Request(`{ films(filmIds: [4, 2], limit: 1) { filmId } }`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(b).To(MatchJSON(`{"films":[{"filmId":2}]}`))
+		})
+
+		Context("when the 'ratings' and 'specialFeatures' parameters are provided", func() {
+			It("passes their values to the film service", func() {
+				var filmParams sakila.FilmParams
+
+				filmService.GetFilmsFn = func(ctx context.Context, params sakila.FilmParams) ([]*sakila.Film, error) {
+					filmParams = params
+					return []*sakila.Film{}, nil
+				}
+
+				query := `
+					{
+						films(ratings: [PG_13, NC_17], specialFeatures: [DELETED_SCENES], filmIds: [1, 2]) {
+							filmId
+						}
+					}
+				`
+
+				_, err := schema.Request(query)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(filmParams.FilmIDs).To(Equal([]int{1, 2}))
+				Expect(filmParams.Ratings).To(Equal([]string{sakila.RatingPG13, sakila.RatingNC17}))
+				Expect(filmParams.SpecialFeatures).To(Equal([]string{sakila.SpecialFeatureDeletedScenes}))
+			})
+		})
 	})
 })
 
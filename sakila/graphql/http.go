@@ -3,15 +3,28 @@ package graphql
 import (
 	"net/http"
 
+	"github.com/gorilla/websocket"
 	"github.com/graphql-go/handler"
 )
 
-// NewHandler returns a new graphql http handler.
+// NewHandler returns a new graphql http handler. Websocket upgrade requests
+// are served by the subscription handler.
 func NewHandler(s *Schema) http.Handler {
-	return handler.New(&handler.Config{
+	h := handler.New(&handler.Config{
 		Schema:     s.Schema,
 		Pretty:     true,
 		GraphiQL:   false,
 		Playground: true,
 	})
+
+	subscriptions := NewSubscriptionHandler(s)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if websocket.IsWebSocketUpgrade(r) {
+			subscriptions.ServeHTTP(w, r)
+			return
+		}
+
+		h.ContextHandler(s.WithLoaders(r.Context()), w, r)
+	})
 }
@@ -0,0 +1,229 @@
+package graphql
+
+import (
+	"context"
+	"expvar"
+	"strconv"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+
+	"github.com/graph-gophers/dataloader"
+)
+
+// Loaders are the data loaders for a single GraphQL operation. The Actor,
+// FilmStores, FilmTranslations and FilmWithActors loaders are nil when the
+// service does not find actors, film stores, film translations or films with
+// their relations.
+type Loaders struct {
+	Film           *dataloader.Loader
+	FilmWithActors *dataloader.Loader
+	Actor          *dataloader.Loader
+	FilmActors     *dataloader.Loader
+	FilmStores     *dataloader.Loader
+
+	FilmTranslations *dataloader.Loader
+}
+
+type loadersContextKey struct{}
+
+const (
+	loaderNameFilm           = "film"
+	loaderNameFilmWithActors = "film_with_actors"
+	loaderNameActor          = "actor"
+	loaderNameFilmActors     = "film_actors"
+	loaderNameFilmStores     = "film_stores"
+
+	loaderNameFilmTranslations = "film_translations"
+)
+
+// loaderMetrics tracks the keys requested from and fetched by the loaders.
+var loaderMetrics = expvar.NewMap("graphql_loaders")
+
+func init() {
+	for _, name := range []string{
+		loaderNameFilm,
+		loaderNameFilmWithActors,
+		loaderNameActor,
+		loaderNameFilmActors,
+		loaderNameFilmStores,
+		loaderNameFilmTranslations,
+	} {
+		name := name
+
+		loaderMetrics.Set(name+"_dedup_ratio", expvar.Func(func() interface{} {
+			return dedupRatio(name)
+		}))
+	}
+}
+
+// NewLoaders returns new data loaders for the given service. The loaders
+// memoize their results, so they should be scoped to a single operation.
+func NewLoaders(service sakila.FilmService, options ...dataloader.Option) *Loaders {
+	loaders := &Loaders{
+		Film:       FilmDataLoader(service, options...),
+		FilmActors: FilmActorsDataLoader(service, options...),
+	}
+
+	if relationService, ok := service.(sakila.FilmRelationService); ok {
+		loaders.FilmWithActors = FilmWithActorsDataLoader(relationService, options...)
+	}
+
+	if actorService, ok := service.(sakila.ActorService); ok {
+		loaders.Actor = ActorDataLoader(actorService, options...)
+	}
+
+	if storeService, ok := service.(sakila.FilmStoreService); ok {
+		loaders.FilmStores = FilmStoresDataLoader(storeService, options...)
+	}
+
+	if translationService, ok := service.(sakila.FilmTranslationService); ok {
+		loaders.FilmTranslations = FilmTranslationsDataLoader(translationService, options...)
+	}
+
+	return loaders
+}
+
+// WithLoaders returns a copy of the context carrying the given loaders.
+func WithLoaders(ctx context.Context, loaders *Loaders) context.Context {
+	return context.WithValue(ctx, loadersContextKey{}, loaders)
+}
+
+// LoadersFromContext returns the loaders carried by the context.
+func LoadersFromContext(ctx context.Context) (*Loaders, bool) {
+	loaders, ok := ctx.Value(loadersContextKey{}).(*Loaders)
+	return loaders, ok
+}
+
+// LoadFilm loads the film with the given ID.
+func (l *Loaders) LoadFilm(ctx context.Context, filmID int) dataloader.Thunk {
+	loaderMetrics.Add(loaderNameFilm+"_requested", 1)
+	return l.Film.Load(ctx, idKey(filmID))
+}
+
+// LoadFilms loads the films with the given IDs.
+func (l *Loaders) LoadFilms(ctx context.Context, filmIDs []int) dataloader.ThunkMany {
+	loaderMetrics.Add(loaderNameFilm+"_requested", int64(len(filmIDs)))
+	return l.Film.LoadMany(ctx, idKeys(filmIDs))
+}
+
+// LoadFilmWithActors loads the film with the given ID along with its actors.
+func (l *Loaders) LoadFilmWithActors(ctx context.Context, filmID int) dataloader.Thunk {
+	loaderMetrics.Add(loaderNameFilmWithActors+"_requested", 1)
+	return l.FilmWithActors.Load(ctx, idKey(filmID))
+}
+
+// LoadActor loads the actor with the given ID.
+func (l *Loaders) LoadActor(ctx context.Context, actorID int) dataloader.Thunk {
+	loaderMetrics.Add(loaderNameActor+"_requested", 1)
+	return l.Actor.Load(ctx, idKey(actorID))
+}
+
+// LoadFilmActors loads the actors of the film with the given ID.
+func (l *Loaders) LoadFilmActors(ctx context.Context, filmID int) dataloader.Thunk {
+	loaderMetrics.Add(loaderNameFilmActors+"_requested", 1)
+	return l.FilmActors.Load(ctx, idKey(filmID))
+}
+
+// LoadFilmStores loads the stores stocking the film with the given ID.
+func (l *Loaders) LoadFilmStores(ctx context.Context, filmID int) dataloader.Thunk {
+	loaderMetrics.Add(loaderNameFilmStores+"_requested", 1)
+	return l.FilmStores.Load(ctx, idKey(filmID))
+}
+
+// LoadFilmTranslation loads the translation of the film with the given ID in
+// the given locale.
+func (l *Loaders) LoadFilmTranslation(ctx context.Context, locale string, filmID int) dataloader.Thunk {
+	loaderMetrics.Add(loaderNameFilmTranslations+"_requested", 1)
+	return l.FilmTranslations.Load(ctx, translationKey(locale, filmID))
+}
+
+// PrimeFilms primes the film loader with the films.
+func (l *Loaders) PrimeFilms(ctx context.Context, films []*sakila.Film) {
+	for _, film := range films {
+		l.Film.Prime(ctx, idKey(film.FilmID), film)
+	}
+}
+
+// PrimeFilmRelations primes the loaders with the loaded relations of the films.
+func (l *Loaders) PrimeFilmRelations(ctx context.Context, films []*sakila.Film, relations sakila.FilmRelations) {
+	for _, film := range films {
+		if relations.Actors {
+			actors := film.Actors
+			if actors == nil {
+				actors = []*sakila.Actor{}
+			}
+
+			l.FilmActors.Prime(ctx, idKey(film.FilmID), actors)
+		}
+	}
+}
+
+// contextLoaders returns a function that returns the loaders for a context,
+// falling back to shared non-memoizing loaders when the context has none.
+func contextLoaders(service sakila.FilmService) func(ctx context.Context) *Loaders {
+	fallback := NewLoaders(service, dataloader.WithCache(&dataloader.NoCache{}))
+
+	return func(ctx context.Context) *Loaders {
+		if loaders, ok := LoadersFromContext(ctx); ok {
+			return loaders
+		}
+
+		return fallback
+	}
+}
+
+func dedupRatio(name string) float64 {
+	requested, ok := loaderMetrics.Get(name + "_requested").(*expvar.Int)
+	if !ok || requested.Value() == 0 {
+		return 0
+	}
+
+	var fetched int64
+	if v, ok := loaderMetrics.Get(name + "_fetched").(*expvar.Int); ok {
+		fetched = v.Value()
+	}
+
+	return 1 - float64(fetched)/float64(requested.Value())
+}
+
+func idKey(id int) dataloader.Key {
+	return dataloader.StringKey(strconv.Itoa(id))
+}
+
+func idKeys(ids []int) dataloader.Keys {
+	keys := make(dataloader.Keys, len(ids))
+	for i := range ids {
+		keys[i] = idKey(ids[i])
+	}
+
+	return keys
+}
+
+// uniqueIDs parses the keys into IDs, dropping duplicates.
+func uniqueIDs(keys dataloader.Keys) ([]int, error) {
+	ids := make([]int, 0, len(keys))
+	seen := make(map[int]bool, len(keys))
+
+	for i := range keys {
+		id, err := strconv.ParseInt(keys[i].String(), 10, 32)
+		if err != nil {
+			return nil, err
+		}
+
+		if !seen[int(id)] {
+			seen[int(id)] = true
+			ids = append(ids, int(id))
+		}
+	}
+
+	return ids, nil
+}
+
+func errorResults(keys dataloader.Keys, err error) []*dataloader.Result {
+	results := make([]*dataloader.Result, len(keys))
+	for i := range keys {
+		results[i] = &dataloader.Result{Error: err}
+	}
+
+	return results
+}
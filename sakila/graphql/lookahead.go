@@ -0,0 +1,117 @@
+package graphql
+
+import (
+	"github.com/nickmro/sakila-service-film/sakila"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// requestedFilmRelations returns the film relations requested in the
+// selection set of the resolved field.
+func requestedFilmRelations(info graphql.ResolveInfo) sakila.FilmRelations {
+	fields := requestedFields(info)
+
+	return sakila.FilmRelations{
+		Actors: fields["actors"],
+	}
+}
+
+// filmFieldsByName are the film fields loaded for each GraphQL film field.
+var filmFieldsByName = map[string][]sakila.FilmField{
+	"__typename":         {sakila.FilmFieldID},
+	"id":                 {sakila.FilmFieldID},
+	"filmId":             {sakila.FilmFieldID},
+	"actors":             {sakila.FilmFieldID},
+	"stores":             {sakila.FilmFieldID},
+	"title":              {sakila.FilmFieldTitle},
+	"description":        {sakila.FilmFieldDescription},
+	"releaseYear":        {sakila.FilmFieldReleaseYear},
+	"languageId":         {sakila.FilmFieldLanguageID},
+	"language":           {sakila.FilmFieldLanguageID},
+	"originalLanguageId": {sakila.FilmFieldOriginalLanguageID},
+	"originalLanguage":   {sakila.FilmFieldOriginalLanguageID},
+	"rentalDuration":     {sakila.FilmFieldRentalDuration},
+	"rentalRate":         {sakila.FilmFieldRentalRate},
+	"length":             {sakila.FilmFieldLength},
+	"replacementCost":    {sakila.FilmFieldReplacementCost},
+	"rating":             {sakila.FilmFieldRating},
+	"specialFeatures":    {sakila.FilmFieldSpecialFeatures},
+	"lastUpdate":         {sakila.FilmFieldLastUpdate},
+}
+
+// requestedFilmFields returns the film fields needed by the selection set of
+// the resolved field, or nil to load every field when a selected field is
+// not known to need only some of them.
+func requestedFilmFields(info graphql.ResolveInfo) []sakila.FilmField {
+	fields := []sakila.FilmField{}
+
+	for name := range requestedFields(info) {
+		filmFields, ok := filmFieldsByName[name]
+		if !ok {
+			return nil
+		}
+
+		fields = append(fields, filmFields...)
+	}
+
+	return fields
+}
+
+// requestedFields returns the names of the fields selected on the resolved
+// field, including the fields selected through fragments.
+func requestedFields(info graphql.ResolveInfo) map[string]bool {
+	fields := map[string]bool{}
+
+	for _, field := range info.FieldASTs {
+		collectFields(info, field.SelectionSet, fields, map[string]bool{})
+	}
+
+	return fields
+}
+
+func collectFields(
+	info graphql.ResolveInfo,
+	selectionSet *ast.SelectionSet,
+	fields map[string]bool,
+	visitedFragments map[string]bool,
+) {
+	if selectionSet == nil {
+		return
+	}
+
+	for _, selection := range selectionSet.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			fields[selection.Name.Value] = true
+		case *ast.InlineFragment:
+			collectFields(info, selection.SelectionSet, fields, visitedFragments)
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			if visitedFragments[name] {
+				continue
+			}
+
+			visitedFragments[name] = true
+
+			if fragment, ok := info.Fragments[name].(*ast.FragmentDefinition); ok {
+				collectFields(info, fragment.SelectionSet, fields, visitedFragments)
+			}
+		}
+	}
+}
+
+// relationService returns the service as a film relation service when it
+// supports loading the requested relations with the films.
+func relationService(
+	service sakila.FilmService,
+	relations sakila.FilmRelations,
+) (sakila.FilmRelationService, bool) {
+	if !relations.Actors {
+		return nil, false
+	}
+
+	relationService, ok := service.(sakila.FilmRelationService)
+
+	return relationService, ok
+}
@@ -0,0 +1,140 @@
+package graphql_test
+
+import (
+	"context"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+	"github.com/nickmro/sakila-service-film/sakila/graphql"
+	"github.com/nickmro/sakila-service-film/sakila/mock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// relationFilmService is a mock film service loading films with their
+// relations.
+type relationFilmService struct {
+	*mock.FilmService
+	getFilmsWithRelationsFn func(
+		ctx context.Context,
+		params sakila.FilmParams,
+		relations sakila.FilmRelations,
+	) ([]*sakila.Film, error)
+}
+
+func (s *relationFilmService) GetFilmsWithRelations(
+	ctx context.Context,
+	params sakila.FilmParams,
+	relations sakila.FilmRelations,
+) ([]*sakila.Film, error) {
+	return s.getFilmsWithRelationsFn(ctx, params, relations)
+}
+
+var _ = Describe("Lookahead", func() {
+	var schema *graphql.Schema
+	var filmService *relationFilmService
+	var relationCalls int
+
+	BeforeEach(func() {
+		relationCalls = 0
+
+		filmService = &relationFilmService{
+			FilmService: &mock.FilmService{
+				GetFilmsFn: func(ctx context.Context, params sakila.FilmParams) ([]*sakila.Film, error) {
+					Fail("unexpected film lookup")
+					return nil, nil
+				},
+				GetFilmActorsFn: func(ctx context.Context, filmIDs ...int) ([]*sakila.FilmActor, error) {
+					Fail("unexpected film actors lookup")
+					return nil, nil
+				},
+			},
+			getFilmsWithRelationsFn: func(
+				ctx context.Context,
+				params sakila.FilmParams,
+				relations sakila.FilmRelations,
+			) ([]*sakila.Film, error) {
+				relationCalls++
+				Expect(relations.Actors).To(BeTrue())
+
+				return []*sakila.Film{
+					{FilmID: 1, Title: "ACADEMY DINOSAUR", Actors: []*sakila.Actor{{ActorID: 1}, {ActorID: 2}}},
+					{FilmID: 2, Title: "ACE GOLDFINGER", Actors: []*sakila.Actor{}},
+				}, nil
+			},
+		}
+
+		s, err := graphql.NewSchema(filmService)
+		if err != nil {
+			panic(err)
+		}
+		schema = s
+	})
+
+	It("loads films with their actors in a single call", func() {
+		b, err := schema.Request(`{ films(ratings: [PG]) { filmId actors { actorId } } }`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(relationCalls).To(Equal(1))
+		Expect(b).To(MatchJSON(`{"films":[
+			{"filmId":1,"actors":[{"actorId":1},{"actorId":2}]},
+			{"filmId":2,"actors":[]}
+		]}`))
+	})
+
+	It("finds actors requested through fragments", func() {
+		b, err := schema.Request(`
+			{ film(filmId: 1) { ...filmActors } }
+			fragment filmActors on Film { actors { actorId } }
+		`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(relationCalls).To(Equal(1))
+		Expect(b).To(MatchJSON(`{"film":{"actors":[{"actorId":1},{"actorId":2}]}}`))
+	})
+
+	It("batches films requested with their actors", func() {
+		filmService.getFilmsWithRelationsFn = func(
+			ctx context.Context,
+			params sakila.FilmParams,
+			relations sakila.FilmRelations,
+		) ([]*sakila.Film, error) {
+			relationCalls++
+			Expect(params.FilmIDs).To(ConsistOf(1, 2))
+
+			return []*sakila.Film{
+				{FilmID: 1, Actors: []*sakila.Actor{{ActorID: 1}}},
+				{FilmID: 2, Actors: []*sakila.Actor{}},
+			}, nil
+		}
+
+		b, err := schema.Request(`{
+			a: film(filmId: 1) { actors { actorId } }
+			b: film(filmId: 2) { actors { actorId } }
+		}`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(relationCalls).To(Equal(1))
+		Expect(b).To(MatchJSON(`{
+			"a":{"actors":[{"actorId":1}]},
+			"b":{"actors":[]}
+		}`))
+	})
+
+	It("returns the films with the requested IDs ordered by ID", func() {
+		b, err := schema.Request(`{ films(filmIds: [2, 1]) { filmId actors { actorId } } }`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b).To(MatchJSON(`{"films":[
+			{"filmId":1,"actors":[{"actorId":1},{"actorId":2}]},
+			{"filmId":2,"actors":[]}
+		]}`))
+	})
+
+	It("loads films without relations when no relations are requested", func() {
+		filmService.GetFilmsFn = func(ctx context.Context, params sakila.FilmParams) ([]*sakila.Film, error) {
+			return []*sakila.Film{{FilmID: 1}}, nil
+		}
+
+		b, err := schema.Request(`{ films(ratings: [PG]) { filmId } }`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(relationCalls).To(Equal(0))
+		Expect(b).To(MatchJSON(`{"films":[{"filmId":1}]}`))
+	})
+})
@@ -0,0 +1,158 @@
+package graphql
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Node type names.
+const (
+	NodeTypeActor = "Actor"
+	NodeTypeFilm  = "Film"
+)
+
+// ErrInvalidGlobalID is returned for a global ID that cannot be decoded.
+var ErrInvalidGlobalID = errors.New("invalid global ID")
+
+// GlobalID returns the opaque global ID of the node with the given type and ID.
+func GlobalID(typeName string, id int) string {
+	return base64.StdEncoding.EncodeToString([]byte(typeName + ":" + strconv.Itoa(id)))
+}
+
+// ParseGlobalID returns the type name and ID of a global ID.
+func ParseGlobalID(globalID string) (typeName string, id int, err error) {
+	b, err := base64.StdEncoding.DecodeString(globalID)
+	if err != nil {
+		return "", 0, fmt.Errorf("%w: %s", ErrInvalidGlobalID, globalID)
+	}
+
+	parts := strings.SplitN(string(b), ":", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", 0, fmt.Errorf("%w: %s", ErrInvalidGlobalID, globalID)
+	}
+
+	id, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return "", 0, fmt.Errorf("%w: %s", ErrInvalidGlobalID, globalID)
+	}
+
+	return parts[0], id, nil
+}
+
+// GlobalIDResolver returns the global ID of a node.
+func GlobalIDResolver() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		switch node := p.Source.(type) {
+		case *sakila.Film:
+			return GlobalID(NodeTypeFilm, node.FilmID), nil
+		case *sakila.Actor:
+			return GlobalID(NodeTypeActor, node.ActorID), nil
+		default:
+			return nil, nil
+		}
+	}
+}
+
+// NodeResolver returns the node with the given global ID.
+func NodeResolver(service sakila.FilmService) graphql.FieldResolveFn {
+	loaders := contextLoaders(service)
+
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		globalID, _ := params.Args["id"].(string)
+
+		thunk, err := nodeThunk(params.Context, loaders(params.Context), globalID)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() (interface{}, error) {
+			node, err := thunk()
+			if errors.Is(err, sakila.ErrorNotFound) {
+				return nil, nil
+			}
+
+			return node, err
+		}, nil
+	}
+}
+
+// NodesResolver returns the nodes with the given global IDs, loading films and
+// actors in a single batch each.
+func NodesResolver(service sakila.FilmService) graphql.FieldResolveFn {
+	loaders := contextLoaders(service)
+
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		globalIDs, _ := params.Args["ids"].([]interface{})
+
+		thunks := make([]func() (interface{}, error), len(globalIDs))
+
+		for i := range globalIDs {
+			globalID, _ := globalIDs[i].(string)
+
+			thunk, err := nodeThunk(params.Context, loaders(params.Context), globalID)
+			if err != nil {
+				return nil, err
+			}
+
+			thunks[i] = thunk
+		}
+
+		return func() (interface{}, error) {
+			nodes := make([]interface{}, len(thunks))
+
+			for i := range thunks {
+				node, err := thunks[i]()
+				if errors.Is(err, sakila.ErrorNotFound) {
+					continue
+				} else if err != nil {
+					return nil, err
+				}
+
+				nodes[i] = node
+			}
+
+			return nodes, nil
+		}, nil
+	}
+}
+
+// ResolveNodeType returns the object type of a node.
+func ResolveNodeType(filmType, actorType *graphql.Object) graphql.ResolveTypeFn {
+	return ResolveEntityType(filmType, actorType)
+}
+
+func nodeThunk(ctx context.Context, loaders *Loaders, globalID string) (func() (interface{}, error), error) {
+	typeName, id, err := ParseGlobalID(globalID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch typeName {
+	case NodeTypeFilm:
+		thunk := loaders.LoadFilm(ctx, id)
+
+		return func() (interface{}, error) {
+			return thunk()
+		}, nil
+	case NodeTypeActor:
+		if loaders.Actor == nil {
+			return nil, fmt.Errorf("%w: unsupported type %s", ErrInvalidGlobalID, typeName)
+		}
+
+		thunk := loaders.LoadActor(ctx, id)
+
+		return func() (interface{}, error) {
+			return thunk()
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown type %s", ErrInvalidGlobalID, typeName)
+	}
+}
@@ -0,0 +1,102 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+	"github.com/nickmro/sakila-service-film/sakila/graphql"
+	"github.com/nickmro/sakila-service-film/sakila/mock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Node", func() {
+	var schema *graphql.Schema
+	var filmService *mock.FilmService
+
+	BeforeEach(func() {
+		filmService = &mock.FilmService{}
+		s, err := graphql.NewSchema(filmService)
+		if err != nil {
+			panic(err)
+		}
+		schema = s
+	})
+
+	Describe("ParseGlobalID", func() {
+		It("decodes a global ID", func() {
+			typeName, id, err := graphql.ParseGlobalID(graphql.GlobalID(graphql.NodeTypeFilm, 42))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(typeName).To(Equal("Film"))
+			Expect(id).To(Equal(42))
+		})
+
+		It("rejects an invalid global ID", func() {
+			_, _, err := graphql.ParseGlobalID("RmlsbTp4")
+			Expect(errors.Is(err, graphql.ErrInvalidGlobalID)).To(BeTrue())
+		})
+	})
+
+	Describe("node", func() {
+		It("returns the film with the global ID", func() {
+			filmService.GetFilmFn = func(ctx context.Context, filmID int) (*sakila.Film, error) {
+				return &sakila.Film{FilmID: filmID, Title: "ACADEMY DINOSAUR"}, nil
+			}
+
+			b, err := schema.Request(`{ node(id: "RmlsbTox") { id ... on Film { title } } }`)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(b).To(MatchJSON(`{"node":{"id":"RmlsbTox","title":"ACADEMY DINOSAUR"}}`))
+		})
+
+		It("returns null when the film is not found", func() {
+			filmService.GetFilmFn = func(ctx context.Context, filmID int) (*sakila.Film, error) {
+				return nil, sakila.ErrorNotFound
+			}
+
+			b, err := schema.Request(`{ node(id: "RmlsbTox") { id } }`)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(b).To(MatchJSON(`{"node":null}`))
+		})
+
+		It("returns null when the actor is not found", func() {
+			b, err := schema.Request(`{ node(id: "QWN0b3I6NQ==") { id } }`)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(b).To(MatchJSON(`{"node":null}`))
+		})
+	})
+
+	Describe("nodes", func() {
+		It("loads the films in a single batch", func() {
+			var calls int
+
+			filmService.GetFilmsFn = func(ctx context.Context, params sakila.FilmParams) ([]*sakila.Film, error) {
+				calls++
+				Expect(params.FilmIDs).To(ConsistOf(1, 2))
+
+				return []*sakila.Film{{FilmID: 1}}, nil
+			}
+
+			filmService.GetActorsFn = func(ctx context.Context, actorIDs ...int) ([]*sakila.Actor, error) {
+				return []*sakila.Actor{{ActorID: 5}}, nil
+			}
+
+			query := `
+				{
+					nodes(ids: ["RmlsbTox", "QWN0b3I6NQ==", "RmlsbToy"]) {
+						id
+						... on Actor {
+							actorId
+						}
+					}
+				}
+			`
+
+			b, err := schema.Request(query)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(calls).To(Equal(1))
+			Expect(b).To(MatchJSON(`{"nodes":[{"id":"RmlsbTox"},{"id":"QWN0b3I6NQ==","actorId":5},null]}`))
+		})
+	})
+})
@@ -1,6 +1,7 @@
 package graphql
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/nickmro/sakila-service-film/sakila"
@@ -11,17 +12,43 @@ import (
 // Schema is a sakila graphQL schema.
 type Schema struct {
 	*graphql.Schema
+	Events sakila.FilmEventSubscriber
+	// SupportedLocales are the locales of the localized fields. They return
+	// the original text when it is empty.
+	SupportedLocales []string
+	service          sakila.FilmService
 }
 
 // NewSchema returns a new graphQL schema.
 func NewSchema(service sakila.FilmService) (*Schema, error) { //nolint:gocyclo
+	s := &Schema{service: service}
+
+	nodeInterface := graphql.NewInterface(
+		graphql.InterfaceConfig{
+			Name:        "Node",
+			Description: "A Node is an object with a global ID.",
+			Fields: graphql.Fields{
+				"id": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.ID),
+					Description: "The global ID.",
+				},
+			},
+		},
+	)
+
 	actorType := graphql.NewObject(
 		graphql.ObjectConfig{
 			Name:        "Actor",
 			Description: "An Actor is a Sakila film actor.",
+			Interfaces:  []*graphql.Interface{nodeInterface},
 			Fields: graphql.Fields{
+				"id": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.ID),
+					Description: "The actor global ID.",
+					Resolve:     GlobalIDResolver(),
+				},
 				"actorId": &graphql.Field{
-					Type:        graphql.Int,
+					Type:        graphql.NewNonNull(graphql.Int),
 					Description: "The actor ID.",
 					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 						if actor, ok := p.Source.(*sakila.Actor); ok {
@@ -35,13 +62,45 @@ func NewSchema(service sakila.FilmService) (*Schema, error) { //nolint:gocyclo
 		},
 	)
 
+	languageType := graphql.NewObject(
+		graphql.ObjectConfig{
+			Name:        "Language",
+			Description: "A Language is a Sakila film language, owned by the language service.",
+			Fields: graphql.Fields{
+				"languageId": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.Int),
+					Description: "The language ID.",
+				},
+			},
+		},
+	)
+
+	storeType := graphql.NewObject(
+		graphql.ObjectConfig{
+			Name:        "Store",
+			Description: "A Store is a Sakila store, owned by the store service.",
+			Fields: graphql.Fields{
+				"storeId": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.Int),
+					Description: "The store ID.",
+				},
+			},
+		},
+	)
+
 	filmType := graphql.NewObject(
 		graphql.ObjectConfig{
 			Name:        "Film",
 			Description: "A Film is a Sakila film.",
+			Interfaces:  []*graphql.Interface{nodeInterface},
 			Fields: graphql.Fields{
+				"id": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.ID),
+					Description: "The film global ID.",
+					Resolve:     GlobalIDResolver(),
+				},
 				"filmId": &graphql.Field{
-					Type:        graphql.Int,
+					Type:        graphql.NewNonNull(graphql.Int),
 					Description: "The film ID.",
 					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 						if film, ok := p.Source.(*sakila.Film); ok {
@@ -52,12 +111,16 @@ func NewSchema(service sakila.FilmService) (*Schema, error) { //nolint:gocyclo
 					},
 				},
 				"title": &graphql.Field{
-					Type:        graphql.String,
+					Type:        graphql.NewNonNull(graphql.String),
 					Description: "The film title.",
+					Args:        localeArgs(),
+					Resolve:     FilmTitleResolver(service, s.supportedLocales),
 				},
 				"description": &graphql.Field{
 					Type:        graphql.String,
 					Description: "The film description.",
+					Args:        localeArgs(),
+					Resolve:     FilmDescriptionResolver(service, s.supportedLocales),
 				},
 				"releaseYear": &graphql.Field{
 					Type:        graphql.Int,
@@ -71,12 +134,12 @@ func NewSchema(service sakila.FilmService) (*Schema, error) { //nolint:gocyclo
 					},
 				},
 				"actors": &graphql.Field{
-					Type:        graphql.NewList(actorType),
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(actorType))),
 					Description: "The film actors.",
 					Resolve:     FilmActorsResolver(service),
 				},
 				"languageId": &graphql.Field{
-					Type:        graphql.Int,
+					Type:        graphql.NewNonNull(graphql.Int),
 					Description: "The film language ID.",
 					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 						if film, ok := p.Source.(*sakila.Film); ok {
@@ -86,6 +149,28 @@ func NewSchema(service sakila.FilmService) (*Schema, error) { //nolint:gocyclo
 						return nil, nil
 					},
 				},
+				"language": &graphql.Field{
+					Type:        graphql.NewNonNull(languageType),
+					Description: "The film language.",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if film, ok := p.Source.(*sakila.Film); ok {
+							return &sakila.Language{LanguageID: film.LanguageID}, nil
+						}
+
+						return nil, nil
+					},
+				},
+				"originalLanguage": &graphql.Field{
+					Type:        languageType,
+					Description: "The film original language.",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if film, ok := p.Source.(*sakila.Film); ok && film.OriginalLanguageID != nil {
+							return &sakila.Language{LanguageID: *film.OriginalLanguageID}, nil
+						}
+
+						return nil, nil
+					},
+				},
 				"originalLanguageId": &graphql.Field{
 					Type:        graphql.Int,
 					Description: "The film original language ID.",
@@ -98,7 +183,7 @@ func NewSchema(service sakila.FilmService) (*Schema, error) { //nolint:gocyclo
 					},
 				},
 				"rentalDuration": &graphql.Field{
-					Type:        graphql.Int,
+					Type:        graphql.NewNonNull(graphql.Int),
 					Description: "The film rental duration.",
 					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 						if film, ok := p.Source.(*sakila.Film); ok {
@@ -109,40 +194,38 @@ func NewSchema(service sakila.FilmService) (*Schema, error) { //nolint:gocyclo
 					},
 				},
 				"rentalRate": &graphql.Field{
-					Type:        graphql.Float,
+					Type:        graphql.NewNonNull(moneyType),
 					Description: "The film rental rate.",
-					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-						if film, ok := p.Source.(*sakila.Film); ok {
-							return film.RentalRate, nil
-						}
-
-						return nil, nil
-					},
+					Args:        moneyArgs(),
+					Resolve: FilmMoneyResolver(func(film *sakila.Film) sakila.Money {
+						return film.RentalRate
+					}),
 				},
 				"length": &graphql.Field{
 					Type:        graphql.Int,
 					Description: "The film length.",
 				},
 				"replacementCost": &graphql.Field{
-					Type:        graphql.Float,
+					Type:        graphql.NewNonNull(moneyType),
 					Description: "The film replacement cost.",
-					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-						if film, ok := p.Source.(*sakila.Film); ok {
-							return film.ReplacementCost, nil
-						}
-
-						return nil, nil
-					},
+					Args:        moneyArgs(),
+					Resolve: FilmMoneyResolver(func(film *sakila.Film) sakila.Money {
+						return film.ReplacementCost
+					}),
 				},
 				"rating": &graphql.Field{
-					Type:        graphql.String,
+					Type:        ratingType,
 					Description: "The film rating.",
 				},
 				"specialFeatures": &graphql.Field{
-					Type:        graphql.NewList(graphql.String),
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(specialFeatureType))),
 					Description: "The film special features.",
 					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 						if film, ok := p.Source.(*sakila.Film); ok {
+							if film.SpecialFeatures == nil {
+								return []string{}, nil
+							}
+
 							return film.SpecialFeatures, nil
 						}
 
@@ -150,7 +233,7 @@ func NewSchema(service sakila.FilmService) (*Schema, error) { //nolint:gocyclo
 					},
 				},
 				"lastUpdate": &graphql.Field{
-					Type:        graphql.DateTime,
+					Type:        graphql.NewNonNull(graphql.DateTime),
 					Description: "The film last update time.",
 					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 						if film, ok := p.Source.(*sakila.Film); ok {
@@ -164,6 +247,24 @@ func NewSchema(service sakila.FilmService) (*Schema, error) { //nolint:gocyclo
 		},
 	)
 
+	if _, ok := service.(sakila.FilmStoreService); ok {
+		filmType.AddFieldConfig("stores", &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(storeType))),
+			Description: "The stores stocking the film.",
+			Resolve:     FilmStoresResolver(service),
+		})
+	}
+
+	nodeInterface.ResolveType = ResolveNodeType(filmType, actorType)
+
+	entityType := graphql.NewUnion(
+		graphql.UnionConfig{
+			Name:        "_Entity",
+			Types:       []*graphql.Object{filmType, actorType},
+			ResolveType: ResolveEntityType(filmType, actorType),
+		},
+	)
+
 	schema, err := graphql.NewSchema(
 		graphql.SchemaConfig{
 			Query: graphql.NewObject(
@@ -183,8 +284,20 @@ func NewSchema(service sakila.FilmService) (*Schema, error) { //nolint:gocyclo
 						},
 						"films": &graphql.Field{
 							Description: "Returns the films for the given parameters",
-							Type:        graphql.NewList(filmType),
+							Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(filmType))),
 							Args: graphql.FieldConfigArgument{
+								"filmIds": &graphql.ArgumentConfig{
+									Type:        graphql.NewList(graphql.Int),
+									Description: "The film IDs.",
+								},
+								"ratings": &graphql.ArgumentConfig{
+									Type:        graphql.NewList(graphql.NewNonNull(ratingType)),
+									Description: "The film ratings. Films with any of the ratings are returned.",
+								},
+								"specialFeatures": &graphql.ArgumentConfig{
+									Type:        graphql.NewList(graphql.NewNonNull(specialFeatureType)),
+									Description: "The film special features. Films with all of the features are returned.",
+								},
 								"limit": &graphql.ArgumentConfig{
 									Type: graphql.Int,
 								},
@@ -194,6 +307,61 @@ func NewSchema(service sakila.FilmService) (*Schema, error) { //nolint:gocyclo
 							},
 							Resolve: FilmsResolver(service),
 						},
+						"node": &graphql.Field{
+							Description: "Returns the node with the given global ID.",
+							Type:        nodeInterface,
+							Args: graphql.FieldConfigArgument{
+								"id": &graphql.ArgumentConfig{
+									Type:        graphql.NewNonNull(graphql.ID),
+									Description: "The global ID.",
+								},
+							},
+							Resolve: NodeResolver(service),
+						},
+						"nodes": &graphql.Field{
+							Description: "Returns the nodes with the given global IDs.",
+							Type:        graphql.NewNonNull(graphql.NewList(nodeInterface)),
+							Args: graphql.FieldConfigArgument{
+								"ids": &graphql.ArgumentConfig{
+									Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.ID))),
+									Description: "The global IDs.",
+								},
+							},
+							Resolve: NodesResolver(service),
+						},
+						"_service": &graphql.Field{
+							Description: "Returns the federated service.",
+							Type:        graphql.NewNonNull(serviceType),
+							Resolve:     ServiceResolver(),
+						},
+						"_entities": &graphql.Field{
+							Description: "Returns the entities for the given representations.",
+							Type:        graphql.NewNonNull(graphql.NewList(entityType)),
+							Args: graphql.FieldConfigArgument{
+								"representations": &graphql.ArgumentConfig{
+									Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(anyType))),
+								},
+							},
+							Resolve: EntitiesResolver(service),
+						},
+					},
+				},
+			),
+			Subscription: graphql.NewObject(
+				graphql.ObjectConfig{
+					Name: "Subscription",
+					Fields: graphql.Fields{
+						"filmUpdated": &graphql.Field{
+							Description: "Returns films as they are updated.",
+							Type:        filmType,
+							Args: graphql.FieldConfigArgument{
+								"filmIds": &graphql.ArgumentConfig{
+									Type:        graphql.NewList(graphql.Int),
+									Description: "The film IDs. All films are returned when omitted.",
+								},
+							},
+							Resolve: FilmUpdatedResolver(service),
+						},
 					},
 				},
 			),
@@ -203,12 +371,29 @@ func NewSchema(service sakila.FilmService) (*Schema, error) { //nolint:gocyclo
 		return nil, err
 	}
 
-	return &Schema{Schema: &schema}, nil
+	s.Schema = &schema
+
+	return s, nil
+}
+
+// supportedLocales returns the supported locales of the localized fields.
+func (s *Schema) supportedLocales() []string {
+	return s.SupportedLocales
+}
+
+// WithLoaders returns a copy of the context carrying new data loaders for a
+// single operation.
+func (s *Schema) WithLoaders(ctx context.Context) context.Context {
+	return WithLoaders(ctx, NewLoaders(s.service))
 }
 
 // Request takes a query to return data from the graphQL service.
 func (s *Schema) Request(query string) ([]byte, error) {
-	params := graphql.Params{Schema: *s.Schema, RequestString: query}
+	params := graphql.Params{
+		Schema:        *s.Schema,
+		RequestString: query,
+		Context:       s.WithLoaders(context.Background()),
+	}
 
 	r := graphql.Do(params)
 	if len(r.Errors) > 0 {
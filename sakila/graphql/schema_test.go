@@ -0,0 +1,42 @@
+package graphql_test
+
+import (
+	"io/ioutil"
+
+	"github.com/nickmro/sakila-service-film/sakila/graphql"
+	"github.com/nickmro/sakila-service-film/sakila/mock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// sdlFile is the reviewed schema SDL. Only breaking changes from it fail, as
+// with go run ./cmd/schema diff. Regenerate it after reviewing a change with:
+//
+//	go run ./cmd/schema > sakila/graphql/schema.graphql
+const sdlFile = "schema.graphql"
+
+var _ = Describe("SDL", func() {
+	var schema *graphql.Schema
+	var reviewedSDL string
+
+	BeforeEach(func() {
+		s, err := graphql.NewSchema(&mock.FilmService{})
+		if err != nil {
+			panic(err)
+		}
+		schema = s
+
+		b, err := ioutil.ReadFile(sdlFile)
+		if err != nil {
+			panic(err)
+		}
+		reviewedSDL = string(b)
+	})
+
+	It("has no unreviewed breaking changes", func() {
+		changes, err := graphql.DiffSDL(reviewedSDL, schema.SDL())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(graphql.BreakingChanges(changes)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,261 @@
+package graphql
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// builtinScalars are the scalars defined by the GraphQL specification.
+var builtinScalars = map[string]bool{
+	"Int":     true,
+	"Float":   true,
+	"String":  true,
+	"Boolean": true,
+	"ID":      true,
+}
+
+// SDL returns the schema definition language of the schema, including its
+// federation directives.
+func (s *Schema) SDL() string {
+	return printSDL(*s.Schema)
+}
+
+// printSDL prints the schema types in name order. Introspection types, builtin
+// scalars and the federation types and fields are not printed.
+func printSDL(schema graphql.Schema) string {
+	typeMap := schema.TypeMap()
+
+	names := make([]string, 0, len(typeMap))
+	for name := range typeMap {
+		if strings.HasPrefix(name, "__") || builtinScalars[name] || federationTypes[name] {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	definitions := make([]string, 0, len(names))
+	for _, name := range names {
+		definitions = append(definitions, printType(typeMap[name]))
+	}
+
+	return strings.Join(definitions, "\n\n") + "\n"
+}
+
+func printType(t graphql.Type) string {
+	switch t := t.(type) {
+	case *graphql.Object:
+		return printObject(t)
+	case *graphql.Interface:
+		return printDescription(t.Description(), "") +
+			"interface " + t.Name() + " {\n" + printFields(t.Name(), t.Fields()) + "}"
+	case *graphql.Union:
+		types := make([]string, len(t.Types()))
+		for i, member := range t.Types() {
+			types[i] = member.Name()
+		}
+
+		return printDescription(t.Description(), "") + "union " + t.Name() + " = " + strings.Join(types, " | ")
+	case *graphql.Enum:
+		return printEnum(t)
+	case *graphql.InputObject:
+		return printInputObject(t)
+	case *graphql.Scalar:
+		return printDescription(t.Description(), "") + "scalar " + t.Name()
+	default:
+		return ""
+	}
+}
+
+func printObject(t *graphql.Object) string {
+	b := strings.Builder{}
+
+	entity, isEntity := entityTypes[t.Name()]
+
+	if isEntity && entity.extended {
+		b.WriteString("extend ")
+	} else {
+		b.WriteString(printDescription(t.PrivateDescription, ""))
+	}
+
+	b.WriteString("type " + t.Name())
+
+	if interfaces := t.Interfaces(); len(interfaces) > 0 {
+		names := make([]string, len(interfaces))
+		for i := range interfaces {
+			names[i] = interfaces[i].Name()
+		}
+
+		b.WriteString(" implements " + strings.Join(names, " & "))
+	}
+
+	if isEntity {
+		b.WriteString(" @key(fields: " + strconv.Quote(entity.keyFields) + ")")
+	}
+
+	b.WriteString(" {\n" + printFields(t.Name(), t.Fields()) + "}")
+
+	return b.String()
+}
+
+func printFields(typeName string, fields graphql.FieldDefinitionMap) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		if typeName == "Query" && federationFields[name] {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	b := strings.Builder{}
+
+	for _, name := range names {
+		field := fields[name]
+
+		b.WriteString(printDescription(field.Description, "  "))
+		b.WriteString("  " + name + printArgs(field.Args) + ": " + field.Type.String())
+
+		if entity, ok := entityTypes[typeName]; ok && entity.extended && entity.keyFields == name {
+			b.WriteString(" @external")
+		}
+
+		b.WriteString(printDeprecation(field.DeprecationReason))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func printArgs(args []*graphql.Argument) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	printed := make([]string, len(args))
+	for i, arg := range args {
+		printed[i] = arg.Name() + ": " + arg.Type.String() + printDefault(arg.Type, arg.DefaultValue)
+	}
+
+	sort.Strings(printed)
+
+	return "(" + strings.Join(printed, ", ") + ")"
+}
+
+func printEnum(t *graphql.Enum) string {
+	b := strings.Builder{}
+
+	b.WriteString(printDescription(t.Description(), ""))
+	b.WriteString("enum " + t.Name() + " {\n")
+
+	values := t.Values()
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Name < values[j].Name
+	})
+
+	for _, value := range values {
+		b.WriteString(printDescription(value.Description, "  "))
+		b.WriteString("  " + value.Name + printDeprecation(value.DeprecationReason) + "\n")
+	}
+
+	b.WriteString("}")
+
+	return b.String()
+}
+
+func printInputObject(t *graphql.InputObject) string {
+	fields := t.Fields()
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	b := strings.Builder{}
+
+	b.WriteString(printDescription(t.Description(), ""))
+	b.WriteString("input " + t.Name() + " {\n")
+
+	for _, name := range names {
+		field := fields[name]
+
+		b.WriteString(printDescription(field.Description(), "  "))
+		b.WriteString("  " + name + ": " + field.Type.String() + printDefault(field.Type, field.DefaultValue) + "\n")
+	}
+
+	b.WriteString("}")
+
+	return b.String()
+}
+
+func printDescription(description, indent string) string {
+	if description == "" {
+		return ""
+	}
+
+	if !strings.Contains(description, "\n") {
+		return indent + strconv.Quote(description) + "\n"
+	}
+
+	quote := indent + `"""` + "\n"
+
+	return quote + indent + strings.ReplaceAll(description, "\n", "\n"+indent) + "\n" + quote
+}
+
+func printDeprecation(reason string) string {
+	if reason == "" {
+		return ""
+	}
+
+	return " @deprecated(reason: " + strconv.Quote(reason) + ")"
+}
+
+func printDefault(t graphql.Type, value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	return " = " + printValue(t, value)
+}
+
+func printValue(t graphql.Type, value interface{}) string {
+	if nonNull, ok := t.(*graphql.NonNull); ok {
+		t = nonNull.OfType
+	}
+
+	switch t := t.(type) {
+	case *graphql.Enum:
+		for _, enumValue := range t.Values() {
+			if reflect.DeepEqual(enumValue.Value, value) {
+				return enumValue.Name
+			}
+		}
+	case *graphql.List:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() == reflect.Slice {
+			values := make([]string, rv.Len())
+			for i := range values {
+				values[i] = printValue(t.OfType, rv.Index(i).Interface())
+			}
+
+			return "[" + strings.Join(values, ", ") + "]"
+		}
+	}
+
+	if s, ok := value.(string); ok {
+		return strconv.Quote(s)
+	}
+
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,69 @@
+package graphql
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+
+	"github.com/graph-gophers/dataloader"
+	"github.com/graphql-go/graphql"
+)
+
+// FilmStoresDataLoader loads data for film stores.
+func FilmStoresDataLoader(service sakila.FilmStoreService, options ...dataloader.Option) *dataloader.Loader {
+	options = append([]dataloader.Option{
+		dataloader.WithBatchCapacity(20),
+	}, options...)
+
+	return dataloader.NewBatchedLoader(func(
+		ctx context.Context,
+		keys dataloader.Keys,
+	) []*dataloader.Result {
+		filmIDs, err := uniqueIDs(keys)
+		if err != nil {
+			return errorResults(keys, err)
+		}
+
+		loaderMetrics.Add(loaderNameFilmStores+"_fetched", int64(len(filmIDs)))
+
+		stores, err := service.GetFilmStores(ctx, filmIDs...)
+		if err != nil {
+			return errorResults(keys, err)
+		}
+
+		filmsMap := map[string][]*sakila.Store{}
+		for _, store := range stores {
+			key := strconv.Itoa(store.FilmID)
+			filmsMap[key] = append(filmsMap[key], &store.Store)
+		}
+
+		results := make([]*dataloader.Result, len(keys))
+		for i := range keys {
+			if stores, ok := filmsMap[keys[i].String()]; ok {
+				results[i] = &dataloader.Result{Data: stores}
+			} else {
+				results[i] = &dataloader.Result{Data: []*sakila.Store{}}
+			}
+		}
+
+		return results
+	}, options...)
+}
+
+// FilmStoresResolver returns the stores stocking the given films.
+func FilmStoresResolver(service sakila.FilmService) graphql.FieldResolveFn {
+	loaders := contextLoaders(service)
+
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		if film, ok := params.Source.(*sakila.Film); ok {
+			thunk := loaders(params.Context).LoadFilmStores(params.Context, film.FilmID)
+
+			return func() (interface{}, error) {
+				return thunk()
+			}, nil
+		}
+
+		return nil, nil
+	}
+}
@@ -0,0 +1,150 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// rootKeyFilmEvent is the root object key of the film event being delivered.
+const rootKeyFilmEvent = "filmEvent"
+
+var (
+	// ErrSubscriptionsDisabled is returned when the schema has no event source.
+	ErrSubscriptionsDisabled = errors.New("subscriptions are disabled")
+	// ErrNotSubscription is returned when the operation is not a subscription.
+	ErrNotSubscription = errors.New("operation is not a subscription")
+)
+
+// FilmUpdatedResolver returns the film of the delivered film event when it
+// matches the requested film IDs.
+func FilmUpdatedResolver(service sakila.FilmService) graphql.FieldResolveFn {
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		root, _ := params.Info.RootValue.(map[string]interface{})
+
+		event, ok := root[rootKeyFilmEvent].(*sakila.FilmEvent)
+		if !ok {
+			return nil, nil
+		}
+
+		if filmIDs, ok := params.Args["filmIds"].([]interface{}); ok && len(filmIDs) > 0 {
+			if !containsID(filmIDs, event.FilmID) {
+				return nil, nil
+			}
+		}
+
+		film, err := service.GetFilm(params.Context, event.FilmID)
+		if errors.Is(err, sakila.ErrorNotFound) {
+			return nil, nil
+		}
+
+		return film, err
+	}
+}
+
+// Subscribe validates a subscription operation and returns a channel
+// receiving its results for every matching film event until the context is
+// done.
+func (s *Schema) Subscribe(
+	ctx context.Context,
+	query string,
+	variables map[string]interface{},
+	operationName string,
+) (<-chan *graphql.Result, error) {
+	if s.Events == nil {
+		return nil, ErrSubscriptionsDisabled
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(query)}),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result := graphql.ValidateDocument(s.Schema, doc, nil); !result.IsValid {
+		return nil, result.Errors[0]
+	}
+
+	if !isSubscription(doc, operationName) {
+		return nil, ErrNotSubscription
+	}
+
+	events := s.Events.SubscribeFilmEvents(ctx)
+	results := make(chan *graphql.Result)
+
+	go func() {
+		defer close(results)
+
+		for event := range events {
+			result := graphql.Do(graphql.Params{
+				Schema:         *s.Schema,
+				RequestString:  query,
+				VariableValues: variables,
+				OperationName:  operationName,
+				RootObject:     map[string]interface{}{rootKeyFilmEvent: event},
+				Context:        s.WithLoaders(ctx),
+			})
+
+			if isEmptyResult(result) {
+				continue
+			}
+
+			select {
+			case results <- result:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results, nil
+}
+
+func isSubscription(doc *ast.Document, operationName string) bool {
+	for _, definition := range doc.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		if operationName == "" || (operation.Name != nil && operation.Name.Value == operationName) {
+			return operation.Operation == ast.OperationTypeSubscription
+		}
+	}
+
+	return false
+}
+
+// isEmptyResult returns whether the result has neither errors nor data, which
+// is the case for events not matching the subscription.
+func isEmptyResult(result *graphql.Result) bool {
+	if len(result.Errors) > 0 {
+		return false
+	}
+
+	data, _ := result.Data.(map[string]interface{})
+	for _, value := range data {
+		if value != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsID(ids []interface{}, id int) bool {
+	for i := range ids {
+		if ids[i] == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,79 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+	"github.com/nickmro/sakila-service-film/sakila/event"
+	"github.com/nickmro/sakila-service-film/sakila/graphql"
+	"github.com/nickmro/sakila-service-film/sakila/mock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Subscription", func() {
+	var schema *graphql.Schema
+	var filmService *mock.FilmService
+	var bus *event.Bus
+	var ctx context.Context
+	var cancel context.CancelFunc
+
+	BeforeEach(func() {
+		filmService = &mock.FilmService{
+			GetFilmFn: func(ctx context.Context, filmID int) (*sakila.Film, error) {
+				return &sakila.Film{FilmID: filmID, Title: "ACADEMY DINOSAUR"}, nil
+			},
+		}
+
+		s, err := graphql.NewSchema(filmService)
+		if err != nil {
+			panic(err)
+		}
+
+		bus = event.NewBus()
+		s.Events = bus
+		schema = s
+
+		ctx, cancel = context.WithCancel(context.Background())
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	Describe("filmUpdated", func() {
+		It("returns the updated films matching the film IDs", func() {
+			query := `
+				subscription {
+					filmUpdated(filmIds: [2]) {
+						filmId
+						title
+					}
+				}
+			`
+
+			results, err := schema.Subscribe(ctx, query, nil, "")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(bus.PublishFilmEvent(ctx, &sakila.FilmEvent{Type: sakila.FilmEventUpdated, FilmID: 1})).To(Succeed())
+			Expect(bus.PublishFilmEvent(ctx, &sakila.FilmEvent{Type: sakila.FilmEventUpdated, FilmID: 2})).To(Succeed())
+
+			var result interface{}
+			Eventually(results, time.Second).Should(Receive(&result))
+
+			b, err := json.Marshal(result)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(b).To(MatchJSON(`{"data":{"filmUpdated":{"filmId":2,"title":"ACADEMY DINOSAUR"}}}`))
+		})
+
+		Context("when the operation is not a subscription", func() {
+			It("returns an error", func() {
+				_, err := schema.Subscribe(ctx, `{ film(filmId: 1) { filmId } }`, nil, "")
+				Expect(err).To(MatchError(graphql.ErrNotSubscription))
+			})
+		})
+	})
+})
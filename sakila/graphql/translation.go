@@ -0,0 +1,163 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+
+	"github.com/graph-gophers/dataloader"
+	"github.com/graphql-go/graphql"
+)
+
+// FilmTranslationsDataLoader loads data for film translations, fetching the
+// translations of each requested locale in a single call.
+func FilmTranslationsDataLoader(
+	service sakila.FilmTranslationService,
+	options ...dataloader.Option,
+) *dataloader.Loader {
+	options = append([]dataloader.Option{
+		dataloader.WithBatchCapacity(20),
+	}, options...)
+
+	return dataloader.NewBatchedLoader(func(
+		ctx context.Context,
+		keys dataloader.Keys,
+	) []*dataloader.Result {
+		localeIDs := map[string][]int{}
+		locales := []string{}
+
+		for i := range keys {
+			locale, filmID, err := parseTranslationKey(keys[i])
+			if err != nil {
+				return errorResults(keys, err)
+			}
+
+			if _, ok := localeIDs[locale]; !ok {
+				locales = append(locales, locale)
+			}
+
+			localeIDs[locale] = append(localeIDs[locale], filmID)
+		}
+
+		translationsMap := make(map[string]*sakila.FilmTranslation, len(keys))
+
+		for _, locale := range locales {
+			filmIDs, _ := uniqueIDs(idKeys(localeIDs[locale]))
+
+			loaderMetrics.Add(loaderNameFilmTranslations+"_fetched", int64(len(filmIDs)))
+
+			translations, err := service.GetFilmTranslations(ctx, locale, filmIDs...)
+			if err != nil {
+				return errorResults(keys, err)
+			}
+
+			for _, translation := range translations {
+				translationsMap[translationKey(locale, translation.FilmID).String()] = translation
+			}
+		}
+
+		results := make([]*dataloader.Result, len(keys))
+		for i := range keys {
+			results[i] = &dataloader.Result{Data: translationsMap[keys[i].String()]}
+		}
+
+		return results
+	}, options...)
+}
+
+// FilmTitleResolver returns the film title in the requested locale.
+func FilmTitleResolver(service sakila.FilmService, supported func() []string) graphql.FieldResolveFn {
+	return filmTranslationResolver(service, supported, func(
+		film *sakila.Film,
+		translation *sakila.FilmTranslation,
+	) interface{} {
+		return film.LocalizedTitle(translation)
+	})
+}
+
+// FilmDescriptionResolver returns the film description in the requested
+// locale.
+func FilmDescriptionResolver(service sakila.FilmService, supported func() []string) graphql.FieldResolveFn {
+	return filmTranslationResolver(service, supported, func(
+		film *sakila.Film,
+		translation *sakila.FilmTranslation,
+	) interface{} {
+		return film.LocalizedDescription(translation)
+	})
+}
+
+// filmTranslationResolver resolves a localized film field. The locale argument
+// takes precedence over the negotiated request locale, and the original text
+// is returned when neither is set or supported, the service does not find
+// film translations, or the film is not translated.
+func filmTranslationResolver(
+	service sakila.FilmService,
+	supported func() []string,
+	localize func(film *sakila.Film, translation *sakila.FilmTranslation) interface{},
+) graphql.FieldResolveFn {
+	loaders := contextLoaders(service)
+
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		film, ok := params.Source.(*sakila.Film)
+		if !ok {
+			return nil, nil
+		}
+
+		locale, _ := params.Args["locale"].(string)
+		if locale == "" {
+			locale = sakila.LocaleFromContext(params.Context)
+		}
+
+		locale = sakila.SupportedLocale(locale, supported())
+
+		operationLoaders := loaders(params.Context)
+
+		if locale == "" || operationLoaders.FilmTranslations == nil {
+			return localize(film, nil), nil
+		}
+
+		thunk := operationLoaders.LoadFilmTranslation(params.Context, locale, film.FilmID)
+
+		return func() (interface{}, error) {
+			data, err := thunk()
+			if err != nil {
+				return nil, err
+			}
+
+			translation, _ := data.(*sakila.FilmTranslation)
+
+			return localize(film, translation), nil
+		}, nil
+	}
+}
+
+// localeArgs returns the arguments of a localized field.
+func localeArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"locale": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "The supported locale, such as \"fr\". Defaults to the Accept-Language locale.",
+		},
+	}
+}
+
+func translationKey(locale string, filmID int) dataloader.Key {
+	return dataloader.StringKey(strconv.Itoa(filmID) + ":" + locale)
+}
+
+func parseTranslationKey(key dataloader.Key) (locale string, filmID int, err error) {
+	parts := strings.SplitN(key.String(), ":", 2)
+	if len(parts) != 2 {
+		return "", 0, fmt.Errorf("invalid translation key: %s", key.String())
+	}
+
+	filmID, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return "", 0, err
+	}
+
+	return parts[1], filmID, nil
+}
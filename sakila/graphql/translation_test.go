@@ -0,0 +1,100 @@
+package graphql_test
+
+import (
+	"context"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+	"github.com/nickmro/sakila-service-film/sakila/graphql"
+	"github.com/nickmro/sakila-service-film/sakila/mock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Translations", func() {
+	var schema *graphql.Schema
+	var filmService *mock.FilmService
+
+	BeforeEach(func() {
+		filmService = &mock.FilmService{}
+		s, err := graphql.NewSchema(filmService)
+		if err != nil {
+			panic(err)
+		}
+		schema = s
+		schema.SupportedLocales = []string{"fr", "de"}
+
+		filmService.GetFilmsFn = func(ctx context.Context, params sakila.FilmParams) ([]*sakila.Film, error) {
+			return []*sakila.Film{
+				{FilmID: 1, Title: "ACADEMY DINOSAUR", Description: stringP("An Epic Drama")},
+				{FilmID: 2, Title: "ACE GOLDFINGER", Description: stringP("An Astounding Epistle")},
+			}, nil
+		}
+	})
+
+	It("returns the original text when no locale is requested", func() {
+		filmService.GetFilmTranslationsFn = func(
+			ctx context.Context,
+			locale string,
+			filmIDs ...int,
+		) ([]*sakila.FilmTranslation, error) {
+			Fail("unexpected translation lookup")
+			return nil, nil
+		}
+
+		b, err := schema.Request(`{ films { title } }`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b).To(MatchJSON(`{"films":[{"title":"ACADEMY DINOSAUR"},{"title":"ACE GOLDFINGER"}]}`))
+	})
+
+	It("loads the translations of a locale in a single batch, falling back to the original text", func() {
+		var calls int
+
+		filmService.GetFilmTranslationsFn = func(
+			ctx context.Context,
+			locale string,
+			filmIDs ...int,
+		) ([]*sakila.FilmTranslation, error) {
+			calls++
+			Expect(locale).To(Equal("fr"))
+			Expect(filmIDs).To(ConsistOf(1, 2))
+
+			return []*sakila.FilmTranslation{
+				{FilmID: 1, Locale: "fr", Title: "DINOSAURE ACADÉMIQUE"},
+			}, nil
+		}
+
+		b, err := schema.Request(`{ films { title(locale: "fr") description(locale: "fr") } }`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(calls).To(Equal(1))
+		Expect(b).To(MatchJSON(`{"films":[
+			{"title":"DINOSAURE ACADÉMIQUE","description":"An Epic Drama"},
+			{"title":"ACE GOLDFINGER","description":"An Astounding Epistle"}
+		]}`))
+	})
+
+	It("returns the original text for an unsupported locale", func() {
+		filmService.GetFilmTranslationsFn = func(
+			ctx context.Context,
+			locale string,
+			filmIDs ...int,
+		) ([]*sakila.FilmTranslation, error) {
+			Fail("unexpected translation lookup")
+			return nil, nil
+		}
+
+		b, err := schema.Request(`{ films { title(locale: "xx-unknown") } }`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b).To(MatchJSON(`{"films":[{"title":"ACADEMY DINOSAUR"},{"title":"ACE GOLDFINGER"}]}`))
+	})
+
+	It("returns the original text when the service does not find translations", func() {
+		s, err := graphql.NewSchema(struct{ sakila.FilmService }{filmService})
+		Expect(err).ToNot(HaveOccurred())
+		s.SupportedLocales = []string{"fr"}
+
+		b, err := s.Request(`{ films { title(locale: "fr") } }`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b).To(MatchJSON(`{"films":[{"title":"ACADEMY DINOSAUR"},{"title":"ACE GOLDFINGER"}]}`))
+	})
+})
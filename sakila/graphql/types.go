@@ -0,0 +1,174 @@
+package graphql
+
+import (
+	"strconv"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// ratingType is the film rating enum.
+var ratingType = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "Rating",
+	Description: "A film rating.",
+	Values: graphql.EnumValueConfigMap{
+		"G": &graphql.EnumValueConfig{
+			Value:       sakila.RatingG,
+			Description: "General audiences.",
+		},
+		"PG": &graphql.EnumValueConfig{
+			Value:       sakila.RatingPG,
+			Description: "Parental guidance suggested.",
+		},
+		"PG_13": &graphql.EnumValueConfig{
+			Value:       sakila.RatingPG13,
+			Description: "Parents strongly cautioned.",
+		},
+		"R": &graphql.EnumValueConfig{
+			Value:       sakila.RatingR,
+			Description: "Restricted.",
+		},
+		"NC_17": &graphql.EnumValueConfig{
+			Value:       sakila.RatingNC17,
+			Description: "Adults only.",
+		},
+	},
+})
+
+// specialFeatureType is the film special feature enum.
+var specialFeatureType = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "SpecialFeature",
+	Description: "A film special feature.",
+	Values: graphql.EnumValueConfigMap{
+		"TRAILERS": &graphql.EnumValueConfig{
+			Value: sakila.SpecialFeatureTrailers,
+		},
+		"COMMENTARIES": &graphql.EnumValueConfig{
+			Value: sakila.SpecialFeatureCommentaries,
+		},
+		"DELETED_SCENES": &graphql.EnumValueConfig{
+			Value: sakila.SpecialFeatureDeletedScenes,
+		},
+		"BEHIND_THE_SCENES": &graphql.EnumValueConfig{
+			Value: sakila.SpecialFeatureBehindTheScenes,
+		},
+	},
+})
+
+const (
+	moneyFormatDecimal  = "decimal"
+	moneyFormatCurrency = "currency"
+)
+
+// moneyType is the scalar for exact monetary amounts, which are serialized as
+// decimal strings to avoid floating point rounding.
+var moneyType = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Money",
+	Description: "A monetary amount in dollars, serialized as a decimal string such as \"4.99\".",
+	Serialize:   serializeMoney,
+	ParseValue:  parseMoney,
+	ParseLiteral: func(value ast.Value) interface{} {
+		switch value := value.(type) {
+		case *ast.FloatValue:
+			return parseMoney(value.Value)
+		case *ast.IntValue:
+			return parseMoney(value.Value)
+		case *ast.StringValue:
+			return parseMoney(value.Value)
+		default:
+			return nil
+		}
+	},
+})
+
+// moneyFormatType is the enum of money field formats.
+var moneyFormatType = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "MoneyFormat",
+	Description: "A monetary amount format.",
+	Values: graphql.EnumValueConfigMap{
+		"DECIMAL": &graphql.EnumValueConfig{
+			Value:       moneyFormatDecimal,
+			Description: "A decimal amount, such as \"4.99\".",
+		},
+		"CURRENCY": &graphql.EnumValueConfig{
+			Value:       moneyFormatCurrency,
+			Description: "An amount with a currency symbol, such as \"$4.99\".",
+		},
+	},
+})
+
+// moneyArgs returns the arguments of a money field.
+func moneyArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"format": &graphql.ArgumentConfig{
+			Type:         moneyFormatType,
+			DefaultValue: moneyFormatDecimal,
+			Description:  "The amount format.",
+		},
+	}
+}
+
+// FilmMoneyResolver returns a film amount in the requested format.
+func FilmMoneyResolver(amount func(film *sakila.Film) sakila.Money) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		film, ok := p.Source.(*sakila.Film)
+		if !ok {
+			return nil, nil
+		}
+
+		if format, _ := p.Args["format"].(string); format == moneyFormatCurrency {
+			return amount(film).Currency(), nil
+		}
+
+		return amount(film), nil
+	}
+}
+
+func serializeMoney(value interface{}) interface{} {
+	switch value := value.(type) {
+	case sakila.Money:
+		return value.String()
+	case *sakila.Money:
+		if value != nil {
+			return value.String()
+		}
+	case string:
+		return value
+	}
+
+	return nil
+}
+
+func parseMoney(value interface{}) interface{} {
+	switch value := value.(type) {
+	case string:
+		if money, err := sakila.ParseMoney(value); err == nil {
+			return money
+		}
+	case int:
+		return sakila.Money(value * 100)
+	case float64:
+		if money, err := sakila.ParseMoney(strconv.FormatFloat(value, 'f', -1, 64)); err == nil {
+			return money
+		}
+	}
+
+	return nil
+}
+
+// stringArgs returns the string values of a list argument.
+func stringArgs(args map[string]interface{}, name string) []string {
+	values, _ := args[name].([]interface{})
+
+	var strs []string
+
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+
+	return strs
+}
@@ -0,0 +1,189 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// subscriptionProtocol is the graphql-ws websocket subprotocol.
+const subscriptionProtocol = "graphql-ws"
+
+const (
+	keepAliveInterval  = time.Second * 15
+	maxMessageSize     = 1 << 16
+	writeTimeout       = time.Second * 10
+	messageTypeInit    = "connection_init"
+	messageTypeAck     = "connection_ack"
+	messageTypeKA      = "ka"
+	messageTypeConnErr = "connection_error"
+	messageTypeStart   = "start"
+	messageTypeStop    = "stop"
+	messageTypeData    = "data"
+	messageTypeError   = "error"
+	messageTypeDone    = "complete"
+	messageTypeEnd     = "connection_terminate"
+)
+
+type subscriptionMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+type subscriptionPayload struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+type subscriptionConn struct {
+	conn          *websocket.Conn
+	schema        *Schema
+	writeMu       sync.Mutex
+	mu            sync.Mutex
+	subscriptions map[string]context.CancelFunc
+}
+
+// NewSubscriptionHandler returns a new graphql-ws websocket handler.
+func NewSubscriptionHandler(s *Schema) http.Handler {
+	upgrader := websocket.Upgrader{
+		Subprotocols: []string{subscriptionProtocol},
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		c := &subscriptionConn{
+			conn:          conn,
+			schema:        s,
+			subscriptions: map[string]context.CancelFunc{},
+		}
+
+		c.serve(r.Context())
+	})
+}
+
+func (c *subscriptionConn) serve(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	defer cancel()
+
+	//nolint:errcheck
+	defer c.conn.Close()
+
+	c.conn.SetReadLimit(maxMessageSize)
+
+	go c.keepAlive(ctx)
+
+	for {
+		var message subscriptionMessage
+		if err := c.conn.ReadJSON(&message); err != nil {
+			return
+		}
+
+		switch message.Type {
+		case messageTypeInit:
+			c.write(&subscriptionMessage{Type: messageTypeAck})
+			c.write(&subscriptionMessage{Type: messageTypeKA})
+		case messageTypeStart:
+			c.start(ctx, &message)
+		case messageTypeStop:
+			c.stop(message.ID)
+		case messageTypeEnd:
+			return
+		default:
+			c.writeError(message.ID, messageTypeConnErr, fmt.Errorf("unknown message type: %s", message.Type))
+		}
+	}
+}
+
+func (c *subscriptionConn) start(ctx context.Context, message *subscriptionMessage) {
+	var payload subscriptionPayload
+	if err := json.Unmarshal(message.Payload, &payload); err != nil {
+		c.writeError(message.ID, messageTypeError, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	c.mu.Lock()
+	if stop, ok := c.subscriptions[message.ID]; ok {
+		stop()
+	}
+	c.subscriptions[message.ID] = cancel
+	c.mu.Unlock()
+
+	results, err := c.schema.Subscribe(ctx, payload.Query, payload.Variables, payload.OperationName)
+	if err != nil {
+		c.stop(message.ID)
+		c.writeError(message.ID, messageTypeError, err)
+
+		return
+	}
+
+	go func() {
+		for result := range results {
+			if b, err := json.Marshal(result); err == nil {
+				c.write(&subscriptionMessage{ID: message.ID, Type: messageTypeData, Payload: b})
+			}
+		}
+
+		c.write(&subscriptionMessage{ID: message.ID, Type: messageTypeDone})
+	}()
+}
+
+func (c *subscriptionConn) stop(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cancel, ok := c.subscriptions[id]; ok {
+		cancel()
+		delete(c.subscriptions, id)
+	}
+}
+
+func (c *subscriptionConn) keepAlive(ctx context.Context) {
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.write(&subscriptionMessage{Type: messageTypeKA})
+		}
+	}
+}
+
+func (c *subscriptionConn) writeError(id, messageType string, err error) {
+	var payload interface{} = gqlerrors.FormatError(err)
+	if messageType == messageTypeError {
+		payload = gqlerrors.FormatErrors(err)
+	}
+
+	if b, err := json.Marshal(payload); err == nil {
+		c.write(&subscriptionMessage{ID: id, Type: messageType, Payload: b})
+	}
+}
+
+func (c *subscriptionConn) write(message *subscriptionMessage) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	//nolint:errcheck
+	c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+
+	//nolint:errcheck
+	c.conn.WriteJSON(message)
+}
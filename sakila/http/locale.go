@@ -0,0 +1,94 @@
+package http
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+)
+
+// Locale returns a middleware that negotiates the request locale from the
+// Accept-Language header and adds it to the request context. Negotiation is
+// disabled, and the original text is returned, when no supported locales are
+// given.
+func Locale(supported []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Language")
+
+			if locale := NegotiateLocale(r.Header.Get("Accept-Language"), supported); locale != "" {
+				r = r.WithContext(sakila.WithLocale(r.Context(), locale))
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// NegotiateLocale returns the supported locale best matching an
+// Accept-Language header, or an empty string when none match or no locales are
+// supported. A language range matches a supported locale exactly or by its
+// primary language.
+func NegotiateLocale(acceptLanguage string, supported []string) string {
+	if len(supported) == 0 {
+		return ""
+	}
+
+	for _, tag := range languageRanges(acceptLanguage) {
+		if locale := sakila.SupportedLocale(tag, supported); locale != "" {
+			return locale
+		}
+	}
+
+	return ""
+}
+
+// languageRanges returns the language ranges of an Accept-Language header in
+// order of preference, skipping wildcards and ranges with a zero weight.
+func languageRanges(acceptLanguage string) []string {
+	type languageRange struct {
+		tag    string
+		weight float64
+	}
+
+	ranges := []languageRange{}
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		params := strings.Split(strings.TrimSpace(part), ";")
+
+		tag := strings.TrimSpace(params[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		weight := 1.0
+
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					weight = q
+				}
+			}
+		}
+
+		if weight > 0 {
+			ranges = append(ranges, languageRange{tag: tag, weight: weight})
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].weight > ranges[j].weight
+	})
+
+	tags := make([]string, len(ranges))
+	for i := range ranges {
+		tags[i] = ranges[i].tag
+	}
+
+	return tags
+}
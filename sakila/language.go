@@ -0,0 +1,6 @@
+package sakila
+
+// Language is a sakila film language.
+type Language struct {
+	LanguageID int `json:"languageId"`
+}
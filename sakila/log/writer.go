@@ -7,11 +7,14 @@ import (
 	"github.com/nickmro/sakila-service-film/sakila"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // Writer writes to a log.
 type Writer struct {
 	*zap.Logger
+	level        zap.AtomicLevel
+	defaultLevel zapcore.Level
 }
 
 // Environment represents the logger environment type.
@@ -28,7 +31,7 @@ const (
 
 // NewWriter returns a new logger based on the environment.
 func NewWriter(e Environment) (w *Writer, err error) {
-	var logger *zap.Logger
+	var config zap.Config
 
 	options := []zap.Option{
 		zap.AddCallerSkip(1),
@@ -36,20 +39,39 @@ func NewWriter(e Environment) (w *Writer, err error) {
 
 	switch e {
 	case EnvironmentProduction:
-		logger, err = zap.NewProduction(options...)
+		config = zap.NewProductionConfig()
 	case EnvironmentTest:
-		logger = zap.NewNop()
+		level := zap.NewAtomicLevel()
+		return &Writer{Logger: zap.NewNop(), level: level, defaultLevel: level.Level()}, nil
 	case EnvironmentDevelopment:
 		fallthrough
 	default:
-		logger, err = zap.NewDevelopment(options...)
+		config = zap.NewDevelopmentConfig()
 	}
 
+	logger, err := config.Build(options...)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Writer{Logger: logger}, nil
+	return &Writer{Logger: logger, level: config.Level, defaultLevel: config.Level.Level()}, nil
+}
+
+// SetLevel sets the minimum level of the written logs: debug, info, warn or
+// error. An empty level restores the default level of the environment. It is
+// safe to call while logs are written.
+func (w *Writer) SetLevel(level string) error {
+	l := w.defaultLevel
+
+	if level != "" {
+		if err := l.UnmarshalText([]byte(level)); err != nil {
+			return err
+		}
+	}
+
+	w.level.SetLevel(l)
+
+	return nil
 }
 
 // Error writes an error.
@@ -0,0 +1,263 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+)
+
+// FilmService is a film service backed by an in-memory dataset. Films are
+// ordered, filtered and paged with the same semantics as the MySQL service.
+type FilmService struct {
+	mu           sync.RWMutex
+	films        []*sakila.Film
+	actorIDs     map[int]bool
+	actors       map[int][]int
+	stores       map[int][]int
+	translations map[string]map[int]*sakila.FilmTranslation
+}
+
+// NewFilmService returns a film service loaded with the fixture.
+func NewFilmService(fixture *Fixture) *FilmService {
+	service := &FilmService{}
+	service.Load(fixture)
+
+	return service
+}
+
+// Load replaces the dataset with the fixture.
+func (service *FilmService) Load(fixture *Fixture) {
+	films := make([]*sakila.Film, len(fixture.Films))
+	for i := range fixture.Films {
+		films[i] = copyFilm(fixture.Films[i])
+	}
+
+	sort.Slice(films, func(i, j int) bool {
+		return films[i].FilmID < films[j].FilmID
+	})
+
+	actorIDs := map[int]bool{}
+	for _, actor := range fixture.Actors {
+		actorIDs[actor.ActorID] = true
+	}
+
+	actors := map[int][]int{}
+	for _, actor := range fixture.FilmActors {
+		actors[actor.FilmID] = appendUnique(actors[actor.FilmID], actor.ActorID)
+	}
+
+	stores := map[int][]int{}
+	for _, store := range fixture.FilmStores {
+		stores[store.FilmID] = appendUnique(stores[store.FilmID], store.StoreID)
+	}
+
+	translations := map[string]map[int]*sakila.FilmTranslation{}
+
+	for _, translation := range fixture.FilmTranslations {
+		if translations[translation.Locale] == nil {
+			translations[translation.Locale] = map[int]*sakila.FilmTranslation{}
+		}
+
+		t := *translation
+		translations[translation.Locale][translation.FilmID] = &t
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	service.films = films
+	service.actorIDs = actorIDs
+	service.actors = actors
+	service.stores = stores
+	service.translations = translations
+}
+
+// GetFilm returns a film.
+func (service *FilmService) GetFilm(ctx context.Context, filmID int) (*sakila.Film, error) {
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+
+	i := sort.Search(len(service.films), func(i int) bool {
+		return service.films[i].FilmID >= filmID
+	})
+
+	if i == len(service.films) || service.films[i].FilmID != filmID {
+		return nil, sakila.ErrorNotFound
+	}
+
+	return copyFilm(service.films[i]), nil
+}
+
+// GetFilms returns the films.
+func (service *FilmService) GetFilms(ctx context.Context, params sakila.FilmParams) ([]*sakila.Film, error) {
+	films := []*sakila.Film{}
+
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+
+	skipped := 0
+
+	for _, film := range service.films {
+		if params.Limit > 0 && len(films) == params.Limit {
+			break
+		}
+
+		if !filmMatches(film, params) {
+			continue
+		}
+
+		if skipped < params.Offset {
+			skipped++
+			continue
+		}
+
+		films = append(films, copyFilm(film))
+	}
+
+	return films, nil
+}
+
+// GetFilmActors returns a film's actors.
+func (service *FilmService) GetFilmActors(ctx context.Context, filmIDs ...int) ([]*sakila.FilmActor, error) {
+	actors := []*sakila.FilmActor{}
+
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+
+	for _, filmID := range uniqueIDs(filmIDs) {
+		for _, actorID := range service.actors[filmID] {
+			actors = append(actors, &sakila.FilmActor{
+				Actor:  sakila.Actor{ActorID: actorID},
+				FilmID: filmID,
+			})
+		}
+	}
+
+	return actors, nil
+}
+
+// GetActors returns the actors with the IDs.
+func (service *FilmService) GetActors(ctx context.Context, actorIDs ...int) ([]*sakila.Actor, error) {
+	actors := []*sakila.Actor{}
+
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+
+	for _, actorID := range uniqueIDs(actorIDs) {
+		if service.actorIDs[actorID] {
+			actors = append(actors, &sakila.Actor{ActorID: actorID})
+		}
+	}
+
+	return actors, nil
+}
+
+// GetFilmStores returns the stores stocking the films.
+func (service *FilmService) GetFilmStores(ctx context.Context, filmIDs ...int) ([]*sakila.FilmStore, error) {
+	stores := []*sakila.FilmStore{}
+
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+
+	for _, filmID := range uniqueIDs(filmIDs) {
+		for _, storeID := range service.stores[filmID] {
+			stores = append(stores, &sakila.FilmStore{
+				Store:  sakila.Store{StoreID: storeID},
+				FilmID: filmID,
+			})
+		}
+	}
+
+	return stores, nil
+}
+
+// GetFilmTranslations returns the films' translations for a locale.
+func (service *FilmService) GetFilmTranslations(
+	ctx context.Context,
+	locale string,
+	filmIDs ...int,
+) ([]*sakila.FilmTranslation, error) {
+	translations := []*sakila.FilmTranslation{}
+
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+
+	for _, filmID := range uniqueIDs(filmIDs) {
+		if translation, ok := service.translations[locale][filmID]; ok {
+			t := *translation
+			translations = append(translations, &t)
+		}
+	}
+
+	return translations, nil
+}
+
+// filmMatches returns whether the film matches the film IDs, any of the
+// ratings and all of the special features of the params.
+func filmMatches(film *sakila.Film, params sakila.FilmParams) bool {
+	if len(params.FilmIDs) > 0 && !containsInt(params.FilmIDs, film.FilmID) {
+		return false
+	}
+
+	if len(params.Ratings) > 0 && (film.Rating == nil || !containsString(params.Ratings, *film.Rating)) {
+		return false
+	}
+
+	for _, feature := range params.SpecialFeatures {
+		if !containsString(film.SpecialFeatures, feature) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// copyFilm returns a copy of the film, so that callers cannot modify the
+// dataset.
+func copyFilm(film *sakila.Film) *sakila.Film {
+	f := *film
+
+	f.SpecialFeatures = append([]string{}, film.SpecialFeatures...)
+	f.Actors = nil
+
+	return &f
+}
+
+func uniqueIDs(ids []int) []int {
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		unique = appendUnique(unique, id)
+	}
+
+	return unique
+}
+
+func appendUnique(ids []int, id int) []int {
+	if containsInt(ids, id) {
+		return ids
+	}
+
+	return append(ids, id)
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,62 @@
+package memory_test
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+	"github.com/nickmro/sakila-service-film/sakila/memory"
+	"github.com/nickmro/sakila-service-film/sakila/sakilatest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Memory", func() {
+	var fixture *memory.Fixture
+
+	BeforeEach(func() {
+		f, err := memory.ReadFixture(strings.NewReader(sakilatest.FixtureJSON))
+		Expect(err).ToNot(HaveOccurred())
+		fixture = f
+	})
+
+	sakilatest.DescribeFilmService(func() sakila.FilmService {
+		return memory.NewFilmService(fixture)
+	})
+
+	It("does not share films with its callers", func() {
+		service := memory.NewFilmService(fixture)
+
+		film, err := service.GetFilm(context.Background(), 1)
+		Expect(err).ToNot(HaveOccurred())
+		film.Title = "CHANGED"
+		film.SpecialFeatures[0] = "CHANGED"
+
+		film, err = service.GetFilm(context.Background(), 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(film.Title).To(Equal("ACADEMY DINOSAUR"))
+		Expect(film.SpecialFeatures[0]).To(Equal(sakila.SpecialFeatureDeletedScenes))
+	})
+
+	It("replaces the dataset on load", func() {
+		service := memory.NewFilmService(fixture)
+		service.Load(&memory.Fixture{
+			Films: []*sakila.Film{{FilmID: 10, Title: "ALADDIN CALENDAR"}},
+		})
+
+		films, err := service.GetFilms(context.Background(), sakila.FilmParams{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(films).To(HaveLen(1))
+		Expect(films[0].Title).To(Equal("ALADDIN CALENDAR"))
+
+		actors, err := service.GetFilmActors(context.Background(), 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actors).To(BeEmpty())
+	})
+
+	It("returns an error for an invalid fixture", func() {
+		_, err := memory.ReadFixture(strings.NewReader(`{"films": [{"rentalRate": "free"}]}`))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,43 @@
+// Package memory implements the film service in memory, for running the
+// service and its tests without a database.
+package memory
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+)
+
+// Fixture is a film dataset.
+type Fixture struct {
+	Films            []*sakila.Film            `json:"films"`
+	Actors           []*sakila.Actor           `json:"actors"`
+	FilmActors       []*FixtureFilmActor       `json:"filmActors"`
+	FilmStores       []*FixtureFilmStore       `json:"filmStores"`
+	FilmTranslations []*sakila.FilmTranslation `json:"filmTranslations"`
+}
+
+// FixtureFilmActor is an actor appearing in a fixture film.
+type FixtureFilmActor struct {
+	FilmID  int `json:"filmId"`
+	ActorID int `json:"actorId"`
+}
+
+// FixtureFilmStore is a store stocking a fixture film, with one entry per
+// copy in stock.
+type FixtureFilmStore struct {
+	FilmID  int `json:"filmId"`
+	StoreID int `json:"storeId"`
+}
+
+// ReadFixture decodes a JSON fixture.
+func ReadFixture(r io.Reader) (*Fixture, error) {
+	var fixture Fixture
+
+	if err := json.NewDecoder(r).Decode(&fixture); err != nil {
+		return nil, err
+	}
+
+	return &fixture, nil
+}
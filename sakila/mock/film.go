@@ -11,6 +11,10 @@ type FilmService struct {
 	GetFilmFn       func(ctx context.Context, filmID int) (*sakila.Film, error)
 	GetFilmsFn      func(ctx context.Context, params sakila.FilmParams) ([]*sakila.Film, error)
 	GetFilmActorsFn func(ctx context.Context, filmIDs ...int) ([]*sakila.FilmActor, error)
+	GetFilmStoresFn func(ctx context.Context, filmIDs ...int) ([]*sakila.FilmStore, error)
+	GetActorsFn     func(ctx context.Context, actorIDs ...int) ([]*sakila.Actor, error)
+
+	GetFilmTranslationsFn func(ctx context.Context, locale string, filmIDs ...int) ([]*sakila.FilmTranslation, error)
 }
 
 // GetFilm runs the mock function or returns an empty film.
@@ -39,3 +43,35 @@ func (s *FilmService) GetFilmActors(ctx context.Context, filmIDs ...int) ([]*sak
 
 	return []*sakila.FilmActor{}, nil
 }
+
+// GetFilmStores runs the mock function or returns an empty slice of film stores.
+func (s *FilmService) GetFilmStores(ctx context.Context, filmIDs ...int) ([]*sakila.FilmStore, error) {
+	if fn := s.GetFilmStoresFn; fn != nil {
+		return fn(ctx, filmIDs...)
+	}
+
+	return []*sakila.FilmStore{}, nil
+}
+
+// GetActors runs the mock function or returns an empty slice of actors.
+func (s *FilmService) GetActors(ctx context.Context, actorIDs ...int) ([]*sakila.Actor, error) {
+	if fn := s.GetActorsFn; fn != nil {
+		return fn(ctx, actorIDs...)
+	}
+
+	return []*sakila.Actor{}, nil
+}
+
+// GetFilmTranslations runs the mock function or returns an empty slice of film
+// translations.
+func (s *FilmService) GetFilmTranslations(
+	ctx context.Context,
+	locale string,
+	filmIDs ...int,
+) ([]*sakila.FilmTranslation, error) {
+	if fn := s.GetFilmTranslationsFn; fn != nil {
+		return fn(ctx, locale, filmIDs...)
+	}
+
+	return []*sakila.FilmTranslation{}, nil
+}
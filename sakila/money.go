@@ -0,0 +1,122 @@
+package sakila
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Money is an exact monetary amount in cents.
+type Money int64
+
+// ParseMoney parses a decimal amount with at most two fractional digits.
+func ParseMoney(s string) (Money, error) {
+	amount := strings.TrimSpace(s)
+
+	negative := strings.HasPrefix(amount, "-")
+	amount = strings.TrimPrefix(amount, "-")
+
+	units, fraction := amount, ""
+	if i := strings.IndexByte(amount, '.'); i >= 0 {
+		units, fraction = amount[:i], amount[i+1:]
+	}
+
+	if units == "" || len(fraction) > 2 {
+		return 0, fmt.Errorf("invalid money amount: %q", s)
+	}
+
+	fraction += strings.Repeat("0", 2-len(fraction))
+
+	cents, err := strconv.ParseInt(units+fraction, 10, 64)
+	if err != nil || strings.ContainsAny(units+fraction, "+-") {
+		return 0, fmt.Errorf("invalid money amount: %q", s)
+	}
+
+	if negative {
+		cents = -cents
+	}
+
+	return Money(cents), nil
+}
+
+// String returns the decimal amount, such as "4.99".
+func (m Money) String() string {
+	cents := int64(m)
+
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// Currency returns the amount formatted in dollars, such as "$4.99".
+func (m Money) Currency() string {
+	if m < 0 {
+		return "-$" + (-m).String()
+	}
+
+	return "$" + m.String()
+}
+
+// MarshalJSON encodes the amount as a decimal string.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON decodes the amount from a decimal string or number.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		var n json.Number
+		if err := json.Unmarshal(b, &n); err != nil {
+			return err
+		}
+
+		s = n.String()
+	}
+
+	money, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+
+	*m = money
+
+	return nil
+}
+
+// Scan scans the amount from a DECIMAL column.
+func (m *Money) Scan(src interface{}) error {
+	var s string
+
+	switch src := src.(type) {
+	case []byte:
+		s = string(src)
+	case string:
+		s = src
+	case int64:
+		*m = Money(src * 100)
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into money", src)
+	}
+
+	money, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+
+	*m = money
+
+	return nil
+}
+
+// Value returns the amount as a decimal string.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
@@ -0,0 +1,204 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/nickmro/mrqb"
+	"github.com/nickmro/sakila-service-film/sakila"
+)
+
+// DefaultChangeFeedInterval is the default change feed polling interval.
+const DefaultChangeFeedInterval = time.Second * 5
+
+// changeTable is a table polled by the change feed.
+type changeTable struct {
+	name      string
+	eventType sakila.FilmEventType
+}
+
+// changeTables are the tables polled by the change feed, keyed by film ID.
+// Missing optional tables, such as film_translation, are skipped.
+var changeTables = []changeTable{
+	{name: "film", eventType: sakila.FilmEventUpdated},
+	{name: "film_actor", eventType: sakila.FilmEventActorsUpdated},
+	{name: "film_category", eventType: sakila.FilmEventCategoriesUpdated},
+	{name: "inventory", eventType: sakila.FilmEventInventoryUpdated},
+	{name: "film_translation", eventType: sakila.FilmEventTranslationsUpdated},
+}
+
+// FilmChangeFeed detects film changes made directly in the database by
+// polling the last_update column of the film tables, and publishes them as
+// film events. Only rows updated before the current second are read, so rows
+// sharing a last_update second are never split across polls. Deleted rows are
+// not detected.
+type FilmChangeFeed struct {
+	DB        *DB
+	Publisher sakila.FilmEventPublisher
+	Interval  time.Duration
+	Logger    sakila.Logger
+
+	tables     []changeTable
+	watermarks map[string]time.Time
+	maxFilmID  int
+}
+
+// Run polls for changes until the context is done.
+func (feed *FilmChangeFeed) Run(ctx context.Context) error {
+	if err := feed.init(ctx); err != nil {
+		return err
+	}
+
+	interval := feed.Interval
+	if interval <= 0 {
+		interval = DefaultChangeFeedInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := feed.Poll(ctx); err != nil {
+				feed.logError(err)
+			}
+		}
+	}
+}
+
+// Poll publishes the changes made since the previous poll.
+func (feed *FilmChangeFeed) Poll(ctx context.Context) error {
+	if feed.watermarks == nil {
+		if err := feed.init(ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, table := range feed.tables {
+		events, err := feed.changes(ctx, table)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if err := feed.Publisher.PublishFilmEvent(ctx, event); err != nil {
+				feed.logError(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// init sets the watermarks to the latest changes made before the current
+// second, so that changes made before the feed started are not published.
+func (feed *FilmChangeFeed) init(ctx context.Context) error {
+	feed.tables = make([]changeTable, 0, len(changeTables))
+	feed.watermarks = make(map[string]time.Time, len(changeTables))
+
+	for _, table := range changeTables {
+		exists, err := feed.tableExists(ctx, table.name)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			feed.logInfo("change feed: skipping missing table", table.name)
+			continue
+		}
+
+		var lastUpdate sql.NullTime
+
+		query, args := mrqb.Select("MAX(last_update)").
+			From(table.name).
+			Where("last_update < CURRENT_TIMESTAMP").
+			Build()
+
+		if err := feed.DB.QueryRowContext(ctx, query, args...).Scan(&lastUpdate); err != nil {
+			return err
+		}
+
+		feed.tables = append(feed.tables, table)
+		feed.watermarks[table.name] = lastUpdate.Time
+	}
+
+	query, args := mrqb.Select("COALESCE(MAX(film_id), 0)").From("film").Build()
+
+	return feed.DB.QueryRowContext(ctx, query, args...).Scan(&feed.maxFilmID)
+}
+
+func (feed *FilmChangeFeed) changes(ctx context.Context, table changeTable) ([]*sakila.FilmEvent, error) {
+	events := []*sakila.FilmEvent{}
+
+	query, args := mrqb.Select(
+		"film_id",
+		"MAX(last_update)",
+	).
+		From(table.name).
+		Where("last_update > %v", feed.watermarks[table.name]).
+		Where("last_update < CURRENT_TIMESTAMP").
+		GroupBy("film_id").
+		OrderBy("MAX(last_update)").
+		Build()
+
+	rows, err := feed.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close() //nolint:errcheck
+
+	for rows.Next() {
+		event := &sakila.FilmEvent{Type: table.eventType}
+
+		if err := rows.Scan(&event.FilmID, &event.Time); err != nil {
+			return nil, err
+		}
+
+		if table.name == "film" && event.FilmID > feed.maxFilmID {
+			event.Type = sakila.FilmEventCreated
+			feed.maxFilmID = event.FilmID
+		}
+
+		if event.Time.After(feed.watermarks[table.name]) {
+			feed.watermarks[table.name] = event.Time
+		}
+
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// tableExists returns whether the table exists in the current database.
+func (feed *FilmChangeFeed) tableExists(ctx context.Context, name string) (bool, error) {
+	var count int
+
+	query, args := mrqb.Select("COUNT(*)").
+		From("information_schema.tables").
+		Where("table_schema = DATABASE()").
+		Where("table_name = %v", name).
+		Build()
+
+	if err := feed.DB.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (feed *FilmChangeFeed) logInfo(args ...interface{}) {
+	if logger := feed.Logger; logger != nil {
+		logger.Info(args...)
+	}
+}
+
+func (feed *FilmChangeFeed) logError(err error) {
+	if logger := feed.Logger; logger != nil {
+		logger.Error(err)
+	}
+}
@@ -1,14 +1,27 @@
 package mysql
 
 import (
+	"container/list"
 	"context"
 	"database/sql"
+	"sync"
 	"time"
+
+	"github.com/nickmro/sakila-service-film/sakila"
 )
 
-// DB is a SQL DB connection.
+// DB is a SQL DB connection. Film queries run with prepared statements, the
+// least recently used of which are closed once MaxPreparedStatements are kept.
 type DB struct {
 	*sql.DB
+	// MaxPreparedStatements is the number of statements kept prepared,
+	// DefaultMaxPreparedStatements when zero.
+	MaxPreparedStatements int
+	Logger                sakila.Logger
+
+	stmtMu  sync.Mutex
+	stmts   map[string]*list.Element
+	stmtLRU list.List
 }
 
 const pingTimeoutDuration = time.Second * 5
@@ -20,3 +33,14 @@ func (db *DB) Status() (interface{}, error) {
 
 	return nil, db.DB.PingContext(ctx)
 }
+
+// Close closes the prepared statements and the DB connection.
+func (db *DB) Close() error {
+	stmtErr := db.closeStmts()
+
+	if err := db.DB.Close(); err != nil {
+		return err
+	}
+
+	return stmtErr
+}
@@ -3,13 +3,17 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"math"
 	"strings"
 
-	"github.com/nickmro/mrqb"
 	"github.com/nickmro/sakila-service-film/sakila"
 )
 
+// noLimit is the limit of a query with an offset but no limit.
+const noLimit = math.MaxInt32
+
 // FilmService is a film service backed by a MySQL DB.
 type FilmService struct {
 	DB     *DB
@@ -18,32 +22,14 @@ type FilmService struct {
 
 // GetFilm returns a film.
 func (service *FilmService) GetFilm(ctx context.Context, filmID int) (*sakila.Film, error) {
-	var film sakila.Film
-	var specialFeatures string
+	var row filmRow
 
 	query, args := filmQueryForParams(sakila.FilmParams{
 		FilmIDs: []int{filmID},
 		Limit:   1,
 	})
 
-	err := service.DB.QueryRow(query, args...).Scan(
-		&film.FilmID,
-		&film.Title,
-		&film.Description,
-		&film.ReleaseYear,
-		&film.LanguageID,
-		&film.OriginalLanguageID,
-		&film.RentalDuration,
-		&film.RentalRate,
-		&film.Length,
-		&film.ReplacementCost,
-		&film.Rating,
-		&specialFeatures,
-		&film.LastUpdate,
-	)
-
-	film.SpecialFeatures = strings.Split(specialFeatures, ",")
-
+	err := service.DB.queryRowContext(ctx, query, args...).Scan(row.dest(filmColumns, sakila.FilmRelations{})...)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, sakila.ErrorNotFound
 	} else if err != nil {
@@ -51,18 +37,36 @@ func (service *FilmService) GetFilm(ctx context.Context, filmID int) (*sakila.Fi
 		return nil, sakila.ErrorInternal
 	}
 
-	return &film, nil
+	film, err := row.result(filmColumns, sakila.FilmRelations{})
+	if err != nil {
+		service.logError(err)
+		return nil, sakila.ErrorInternal
+	}
+
+	return film, nil
 }
 
 // GetFilms returns the films.
 func (service *FilmService) GetFilms(ctx context.Context, params sakila.FilmParams) ([]*sakila.Film, error) {
-	films := []*sakila.Film{}
+	return service.GetFilmsWithRelations(ctx, params, sakila.FilmRelations{})
+}
+
+// GetFilmActors returns a film's actors.
+func (service *FilmService) GetFilmActors(ctx context.Context, filmIDs ...int) ([]*sakila.FilmActor, error) {
+	actors := []*sakila.FilmActor{}
+
+	if len(filmIDs) == 0 {
+		return actors, nil
+	}
+
+	ids := bucketedIDs(filmIDs)
 
-	query, args := filmQueryForParams(params)
+	query := "SELECT film_actor.film_id, film_actor.actor_id FROM film_actor " +
+		"WHERE film_actor.film_id IN (" + placeholders(len(ids)) + ")"
 
-	rows, err := service.DB.Query(query, args...)
+	rows, err := service.DB.queryContext(ctx, query, ids...)
 	if errors.Is(err, sql.ErrNoRows) {
-		return films, nil
+		return actors, nil
 	} else if err != nil {
 		service.logError(err)
 		return nil, sakila.ErrorInternal
@@ -74,61 +78,125 @@ func (service *FilmService) GetFilms(ctx context.Context, params sakila.FilmPara
 	defer rows.Close() //nolint:errcheck
 
 	for rows.Next() {
-		var film sakila.Film
-		var specialFeatures string
+		var actor sakila.FilmActor
 
 		if err := rows.Scan(
-			&film.FilmID,
-			&film.Title,
-			&film.Description,
-			&film.ReleaseYear,
-			&film.LanguageID,
-			&film.OriginalLanguageID,
-			&film.RentalDuration,
-			&film.RentalRate,
-			&film.Length,
-			&film.ReplacementCost,
-			&film.Rating,
-			&specialFeatures,
-			&film.LastUpdate,
+			&actor.FilmID,
+			&actor.ActorID,
 		); err != nil {
 			service.logError(err)
 			return nil, sakila.ErrorInternal
 		}
 
-		film.SpecialFeatures = strings.Split(specialFeatures, ",")
+		actors = append(actors, &actor)
+	}
+
+	return actors, nil
+}
+
+// GetActors returns the actors with the IDs.
+func (service *FilmService) GetActors(ctx context.Context, actorIDs ...int) ([]*sakila.Actor, error) {
+	actors := []*sakila.Actor{}
 
-		films = append(films, &film)
+	if len(actorIDs) == 0 {
+		return actors, nil
 	}
 
-	return films, nil
+	ids := bucketedIDs(actorIDs)
+
+	query := "SELECT actor.actor_id FROM actor WHERE actor.actor_id IN (" + placeholders(len(ids)) + ")"
+
+	rows, err := service.DB.queryContext(ctx, query, ids...)
+	if err != nil {
+		service.logError(err)
+		return nil, sakila.ErrorInternal
+	}
+
+	defer rows.Close() //nolint:errcheck
+
+	for rows.Next() {
+		var actor sakila.Actor
+
+		if err := rows.Scan(&actor.ActorID); err != nil {
+			service.logError(err)
+			return nil, sakila.ErrorInternal
+		}
+
+		actors = append(actors, &actor)
+	}
+
+	if err := rows.Err(); err != nil {
+		service.logError(err)
+		return nil, sakila.ErrorInternal
+	}
+
+	return actors, nil
 }
 
-// GetFilmActors returns a film's actors.
-func (service *FilmService) GetFilmActors(ctx context.Context, filmIDs ...int) ([]*sakila.FilmActor, error) {
-	actors := []*sakila.FilmActor{}
+// GetFilmStores returns the stores stocking the films.
+func (service *FilmService) GetFilmStores(ctx context.Context, filmIDs ...int) ([]*sakila.FilmStore, error) {
+	stores := []*sakila.FilmStore{}
 
-	stmt := mrqb.Select(
-		"film_id",
-		"actor_id",
-	).
-		From("film_actor")
+	if len(filmIDs) == 0 {
+		return stores, nil
+	}
+
+	ids := bucketedIDs(filmIDs)
+
+	query := "SELECT DISTINCT inventory.film_id, inventory.store_id FROM inventory " +
+		"WHERE inventory.film_id IN (" + placeholders(len(ids)) + ")"
 
-	if len(filmIDs) == 1 {
-		stmt.Where("film_actor.film_id = %v", filmIDs[0])
-	} else {
-		stmt.Where("film_actor.film_id IN (%v)", formattedIDs(filmIDs)...)
+	rows, err := service.DB.queryContext(ctx, query, ids...)
+	if err != nil {
+		service.logError(err)
+		return nil, sakila.ErrorInternal
 	}
 
-	query, args := stmt.Build()
+	defer rows.Close() //nolint:errcheck
+
+	for rows.Next() {
+		var store sakila.FilmStore
 
-	rows, err := service.DB.Query(query, args...)
-	if errors.Is(err, sql.ErrNoRows) {
-		return actors, nil
-	} else if err != nil {
+		if err := rows.Scan(
+			&store.FilmID,
+			&store.StoreID,
+		); err != nil {
+			service.logError(err)
+			return nil, sakila.ErrorInternal
+		}
+
+		stores = append(stores, &store)
+	}
+
+	if err := rows.Err(); err != nil {
 		service.logError(err)
 		return nil, sakila.ErrorInternal
-	} else if err := rows.Err(); err != nil {
+	}
+
+	return stores, nil
+}
+
+// GetFilmTranslations returns the films' translations for a locale.
+func (service *FilmService) GetFilmTranslations(
+	ctx context.Context,
+	locale string,
+	filmIDs ...int,
+) ([]*sakila.FilmTranslation, error) {
+	translations := []*sakila.FilmTranslation{}
+
+	if len(filmIDs) == 0 {
+		return translations, nil
+	}
+
+	ids := bucketedIDs(filmIDs)
+
+	query := "SELECT film_translation.film_id, film_translation.locale, " +
+		"film_translation.title, film_translation.description FROM film_translation " +
+		"WHERE film_translation.locale = ? AND film_translation.film_id IN (" + placeholders(len(ids)) + ")"
+	args := append([]interface{}{locale}, ids...)
+
+	rows, err := service.DB.queryContext(ctx, query, args...)
+	if err != nil {
 		service.logError(err)
 		return nil, sakila.ErrorInternal
 	}
@@ -136,20 +204,73 @@ func (service *FilmService) GetFilmActors(ctx context.Context, filmIDs ...int) (
 	defer rows.Close() //nolint:errcheck
 
 	for rows.Next() {
-		var actor sakila.FilmActor
+		var translation sakila.FilmTranslation
 
-		err := rows.Scan(
-			&actor.FilmID,
-			&actor.ActorID,
-		)
+		if err := rows.Scan(
+			&translation.FilmID,
+			&translation.Locale,
+			&translation.Title,
+			&translation.Description,
+		); err != nil {
+			service.logError(err)
+			return nil, sakila.ErrorInternal
+		}
+
+		translations = append(translations, &translation)
+	}
+
+	if err := rows.Err(); err != nil {
+		service.logError(err)
+		return nil, sakila.ErrorInternal
+	}
+
+	return translations, nil
+}
+
+// GetFilmsWithRelations returns the films with the requested relations,
+// loaded in a single query.
+func (service *FilmService) GetFilmsWithRelations(
+	ctx context.Context,
+	params sakila.FilmParams,
+	relations sakila.FilmRelations,
+) ([]*sakila.Film, error) {
+	films := []*sakila.Film{}
+
+	columns := projectedFilmColumns(params.Fields)
+
+	query, args := filmRelationsQueryForParams(params, relations)
+
+	rows, err := service.DB.queryContext(ctx, query, args...)
+	if err != nil {
+		service.logError(err)
+		return nil, sakila.ErrorInternal
+	}
+
+	defer rows.Close() //nolint:errcheck
+
+	for rows.Next() {
+		var row filmRow
+
+		if err := rows.Scan(row.dest(columns, relations)...); err != nil {
+			service.logError(err)
+			return nil, sakila.ErrorInternal
+		}
+
+		film, err := row.result(columns, relations)
 		if err != nil {
-			return nil, err
+			service.logError(err)
+			return nil, sakila.ErrorInternal
 		}
 
-		actors = append(actors, &actor)
+		films = append(films, film)
 	}
 
-	return actors, nil
+	if err := rows.Err(); err != nil {
+		service.logError(err)
+		return nil, sakila.ErrorInternal
+	}
+
+	return films, nil
 }
 
 func (service *FilmService) logError(err error) {
@@ -158,48 +279,251 @@ func (service *FilmService) logError(err error) {
 	}
 }
 
+// filmQueryForParams returns the film query of the params. Every value is a
+// query argument, and lists are padded to their bucket size, so that params
+// of the same shape share a prepared statement.
 func filmQueryForParams(params sakila.FilmParams) (query string, args []interface{}) {
-	stmt := mrqb.Select(
-		"film.film_id",
-		"film.title",
-		"film.description",
-		"film.release_year",
-		"film.language_id",
-		"film.original_language_id",
-		"film.rental_duration",
-		"film.rental_rate",
-		"film.length",
-		"film.replacement_cost",
-		"film.rating",
-		"film.special_features",
-		"film.last_update",
-	).
-		From("film")
-
-	if ids := params.FilmIDs; len(ids) > 0 {
-		if len(ids) == 1 {
-			stmt.Where("film.film_id = %v", ids[0])
-		} else {
-			stmt.Where("film.film_id IN (%v)", formattedIDs(ids)...)
+	return filmRelationsQueryForParams(params, sakila.FilmRelations{})
+}
+
+// filmActorsColumn aggregates the IDs of a film's actors into a JSON array,
+// or NULL when the film has no actors.
+const filmActorsColumn = "(SELECT JSON_ARRAYAGG(film_actor.actor_id) FROM film_actor " +
+	"WHERE film_actor.film_id = film.film_id)"
+
+// filmRelationsQueryForParams returns the film query of the params selecting
+// the requested relations after the film columns.
+func filmRelationsQueryForParams(
+	params sakila.FilmParams,
+	relations sakila.FilmRelations,
+) (query string, args []interface{}) {
+	columns := projectedFilmColumns(params.Fields)
+
+	names := make([]string, 0, len(columns)+1)
+	for _, column := range columns {
+		names = append(names, column.name)
+	}
+
+	if relations.Actors {
+		names = append(names, filmActorsColumn)
+	}
+
+	return filmQuery(strings.Join(names, ", "), params)
+}
+
+// filmQuery returns the query selecting the columns of the films matching the
+// params.
+func filmQuery(columns string, params sakila.FilmParams) (query string, args []interface{}) {
+	conditions := []string{}
+
+	if len(params.FilmIDs) > 0 {
+		ids := bucketedIDs(params.FilmIDs)
+		conditions = append(conditions, "film.film_id IN ("+placeholders(len(ids))+")")
+		args = append(args, ids...)
+	}
+
+	if len(params.Ratings) > 0 {
+		ratings := bucketedStrings(params.Ratings)
+		conditions = append(conditions, "film.rating IN ("+placeholders(len(ratings))+")")
+		args = append(args, ratings...)
+	}
+
+	for _, feature := range params.SpecialFeatures {
+		conditions = append(conditions, "FIND_IN_SET(?, film.special_features) > 0")
+		args = append(args, feature)
+	}
+
+	b := strings.Builder{}
+
+	b.WriteString("SELECT " + columns + " FROM film")
+
+	if len(conditions) > 0 {
+		b.WriteString(" WHERE " + strings.Join(conditions, " AND "))
+	}
+
+	b.WriteString(" ORDER BY film.film_id")
+
+	// MySQL requires a limit with an offset.
+	if params.Limit > 0 || params.Offset > 0 {
+		limit := params.Limit
+		if limit <= 0 {
+			limit = noLimit
 		}
+
+		b.WriteString(" LIMIT ? OFFSET ?")
+		args = append(args, limit, params.Offset)
+	}
+
+	return b.String(), args
+}
+
+// filmColumn is a film column and the field it loads.
+type filmColumn struct {
+	field sakila.FilmField
+	name  string
+	dest  func(row *filmRow) interface{}
+}
+
+// filmColumns are the film columns in scan order.
+var filmColumns = []filmColumn{
+	{
+		field: sakila.FilmFieldID,
+		name:  "film.film_id",
+		dest:  func(row *filmRow) interface{} { return &row.film.FilmID },
+	},
+	{
+		field: sakila.FilmFieldTitle,
+		name:  "film.title",
+		dest:  func(row *filmRow) interface{} { return &row.film.Title },
+	},
+	{
+		field: sakila.FilmFieldDescription,
+		name:  "film.description",
+		dest:  func(row *filmRow) interface{} { return &row.film.Description },
+	},
+	{
+		field: sakila.FilmFieldReleaseYear,
+		name:  "film.release_year",
+		dest:  func(row *filmRow) interface{} { return &row.film.ReleaseYear },
+	},
+	{
+		field: sakila.FilmFieldLanguageID,
+		name:  "film.language_id",
+		dest:  func(row *filmRow) interface{} { return &row.film.LanguageID },
+	},
+	{
+		field: sakila.FilmFieldOriginalLanguageID,
+		name:  "film.original_language_id",
+		dest:  func(row *filmRow) interface{} { return &row.film.OriginalLanguageID },
+	},
+	{
+		field: sakila.FilmFieldRentalDuration,
+		name:  "film.rental_duration",
+		dest:  func(row *filmRow) interface{} { return &row.film.RentalDuration },
+	},
+	{
+		field: sakila.FilmFieldRentalRate,
+		name:  "film.rental_rate",
+		dest:  func(row *filmRow) interface{} { return &row.film.RentalRate },
+	},
+	{
+		field: sakila.FilmFieldLength,
+		name:  "film.length",
+		dest:  func(row *filmRow) interface{} { return &row.film.Length },
+	},
+	{
+		field: sakila.FilmFieldReplacementCost,
+		name:  "film.replacement_cost",
+		dest:  func(row *filmRow) interface{} { return &row.film.ReplacementCost },
+	},
+	{
+		field: sakila.FilmFieldRating,
+		name:  "film.rating",
+		dest:  func(row *filmRow) interface{} { return &row.film.Rating },
+	},
+	{
+		field: sakila.FilmFieldSpecialFeatures,
+		name:  "film.special_features",
+		dest:  func(row *filmRow) interface{} { return &row.specialFeatures },
+	},
+	{
+		field: sakila.FilmFieldLastUpdate,
+		name:  "film.last_update",
+		dest:  func(row *filmRow) interface{} { return &row.film.LastUpdate },
+	},
+}
+
+// projectedFilmColumns returns the columns of the film ID and the given
+// fields in scan order, or every column when no fields are given.
+func projectedFilmColumns(fields []sakila.FilmField) []filmColumn {
+	if len(fields) == 0 {
+		return filmColumns
 	}
 
-	if limit := params.Limit; limit > 0 {
-		stmt.Limit(limit)
+	projected := map[sakila.FilmField]bool{}
+	for _, field := range fields {
+		projected[field] = true
 	}
 
-	if offset := params.Offset; offset > 0 {
-		stmt.Offset(offset)
+	columns := []filmColumn{}
+
+	for _, column := range filmColumns {
+		if column.field == sakila.FilmFieldID || projected[column.field] {
+			columns = append(columns, column)
+		}
 	}
 
-	return stmt.Build()
+	return columns
 }
 
-func formattedIDs(ids []int) []interface{} {
-	formattedIDs := make([]interface{}, len(ids))
-	for i := range ids {
-		formattedIDs[i] = ids[i]
+// filmRow is a scanned film row.
+type filmRow struct {
+	film            sakila.Film
+	specialFeatures sql.NullString
+	actorIDs        sql.NullString
+}
+
+// dest returns the scan destinations of the columns and relations.
+func (row *filmRow) dest(columns []filmColumn, relations sakila.FilmRelations) []interface{} {
+	dest := make([]interface{}, 0, len(columns)+1)
+	for _, column := range columns {
+		dest = append(dest, column.dest(row))
+	}
+
+	if relations.Actors {
+		dest = append(dest, &row.actorIDs)
 	}
 
-	return formattedIDs
+	return dest
+}
+
+// result returns the scanned film.
+func (row *filmRow) result(columns []filmColumn, relations sakila.FilmRelations) (*sakila.Film, error) {
+	film := row.film
+
+	for _, column := range columns {
+		if column.field == sakila.FilmFieldSpecialFeatures {
+			film.SpecialFeatures = splitSpecialFeatures(row.specialFeatures)
+		}
+	}
+
+	if relations.Actors {
+		actors, err := unmarshalActors(row.actorIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		film.Actors = actors
+	}
+
+	return &film, nil
+}
+
+// splitSpecialFeatures splits the special features set column.
+func splitSpecialFeatures(specialFeatures sql.NullString) []string {
+	if !specialFeatures.Valid || specialFeatures.String == "" {
+		return []string{}
+	}
+
+	return strings.Split(specialFeatures.String, ",")
+}
+
+// unmarshalActors decodes the actors of a JSON array of actor IDs.
+func unmarshalActors(actorIDs sql.NullString) ([]*sakila.Actor, error) {
+	actors := []*sakila.Actor{}
+
+	if !actorIDs.Valid {
+		return actors, nil
+	}
+
+	var ids []int
+	if err := json.Unmarshal([]byte(actorIDs.String), &ids); err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		actors = append(actors, &sakila.Actor{ActorID: id})
+	}
+
+	return actors, nil
 }
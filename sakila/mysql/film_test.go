@@ -0,0 +1,158 @@
+package mysql_test
+
+import (
+	"context"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+	"github.com/nickmro/sakila-service-film/sakila/mysql"
+	"github.com/nickmro/sakila-service-film/sakila/redis"
+	"github.com/nickmro/sakila-service-film/sakila/sakilatest"
+	"github.com/nickmro/sakila-service-film/sakila/sqlite"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// The MySQL film service runs its queries unchanged against an in-memory
+// SQLite stand-in for the Sakila database.
+var _ = Describe("FilmService", func() {
+	var db *sqlite.DB
+
+	BeforeEach(func() {
+		d, err := sqlite.Open(sqlite.MemoryDataSourceName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(d.Migrate(context.Background())).To(Succeed())
+		Expect(d.Seed(context.Background(), sakilatest.Fixture())).To(Succeed())
+		db = d
+	})
+
+	AfterEach(func() {
+		Expect(db.Close()).To(Succeed())
+	})
+
+	Context("uncached", func() {
+		sakilatest.DescribeFilmService(func() sakila.FilmService {
+			return &mysql.FilmService{DB: &mysql.DB{DB: db.DB}}
+		})
+	})
+
+	Describe("GetFilms", func() {
+		It("loads only the projected fields", func() {
+			service := &mysql.FilmService{DB: &mysql.DB{DB: db.DB}}
+
+			films, err := service.GetFilms(context.Background(), sakila.FilmParams{
+				FilmIDs: []int{1},
+				Fields:  []sakila.FilmField{sakila.FilmFieldTitle, sakila.FilmFieldRentalRate},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(films).To(HaveLen(1))
+			Expect(films[0].FilmID).To(Equal(1))
+			Expect(films[0].Title).To(Equal("ACADEMY DINOSAUR"))
+			Expect(films[0].RentalRate).To(Equal(sakila.Money(99)))
+			Expect(films[0].Description).To(BeNil())
+			Expect(films[0].LastUpdate.IsZero()).To(BeTrue())
+		})
+	})
+
+	Describe("GetFilmsWithRelations", func() {
+		It("returns the films with their actors", func() {
+			service := &mysql.FilmService{DB: &mysql.DB{DB: db.DB}}
+
+			films, err := service.GetFilmsWithRelations(
+				context.Background(),
+				sakila.FilmParams{FilmIDs: []int{1, 2, 6}},
+				sakila.FilmRelations{Actors: true},
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(films).To(HaveLen(3))
+			Expect(films[0].Title).To(Equal("ACADEMY DINOSAUR"))
+			Expect(films[0].Actors).To(ConsistOf(
+				&sakila.Actor{ActorID: 1},
+				&sakila.Actor{ActorID: 2},
+				&sakila.Actor{ActorID: 3},
+			))
+			Expect(films[1].Actors).To(ConsistOf(&sakila.Actor{ActorID: 2}))
+			Expect(films[2].Actors).To(BeEmpty())
+		})
+
+		It("returns the films without relations", func() {
+			service := &mysql.FilmService{DB: &mysql.DB{DB: db.DB}}
+
+			films, err := service.GetFilmsWithRelations(
+				context.Background(),
+				sakila.FilmParams{Limit: 2},
+				sakila.FilmRelations{},
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(films).To(HaveLen(2))
+			Expect(films[0].Actors).To(BeNil())
+		})
+	})
+
+	Describe("prepared statements", func() {
+		It("shares a statement between ID lists of the same bucket", func() {
+			mysqlDB := &mysql.DB{DB: db.DB}
+			service := &mysql.FilmService{DB: mysqlDB}
+
+			_, err := service.GetFilmActors(context.Background(), 1, 2, 3)
+			Expect(err).ToNot(HaveOccurred())
+
+			actors, err := service.GetFilmActors(context.Background(), 2, 3, 4, 5)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actors).To(HaveLen(2))
+			Expect(mysqlDB.PreparedStatements()).To(Equal(1))
+
+			_, err = service.GetFilmActors(context.Background(), 1, 2, 3, 4, 5)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mysqlDB.PreparedStatements()).To(Equal(2))
+		})
+
+		It("closes the least recently used statements", func() {
+			mysqlDB := &mysql.DB{DB: db.DB, MaxPreparedStatements: 2}
+			service := &mysql.FilmService{DB: mysqlDB}
+
+			_, err := service.GetFilmActors(context.Background(), 1)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = service.GetFilmStores(context.Background(), 1)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = service.GetFilmActors(context.Background(), 2)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = service.GetFilmTranslations(context.Background(), sakilatest.LocaleFrench, 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mysqlDB.PreparedStatements()).To(Equal(2))
+
+			actors, err := service.GetFilmActors(context.Background(), 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actors).To(HaveLen(3))
+
+			stores, err := service.GetFilmStores(context.Background(), 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stores).To(HaveLen(2))
+		})
+
+		It("shares a statement between pages", func() {
+			mysqlDB := &mysql.DB{DB: db.DB}
+			service := &mysql.FilmService{DB: mysqlDB}
+
+			_, err := service.GetFilms(context.Background(), sakila.FilmParams{Limit: 2})
+			Expect(err).ToNot(HaveOccurred())
+
+			films, err := service.GetFilms(context.Background(), sakila.FilmParams{Limit: 2, Offset: 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(films).To(HaveLen(2))
+			Expect(mysqlDB.PreparedStatements()).To(Equal(1))
+		})
+	})
+
+	Context("cached", func() {
+		sakilatest.DescribeFilmService(func() sakila.FilmService {
+			return (&redis.FilmService{
+				FilmService: &mysql.FilmService{DB: &mysql.DB{DB: db.DB}},
+				Cache:       redis.NewLocalCache(1000, redis.DefaultTTL),
+			}).Service()
+		})
+	})
+})
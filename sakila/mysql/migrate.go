@@ -0,0 +1,273 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nickmro/mrqb"
+	"github.com/nickmro/sakila-service-film/sakila"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationsTable is the table recording the applied migrations.
+const migrationsTable = "schema_migrations"
+
+// migrationFileName matches migration file names such as
+// 000001_create_sakila_tables.up.sql.
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a versioned schema migration.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus is the status of a migration.
+type MigrationStatus struct {
+	*Migration
+	AppliedAt *time.Time
+}
+
+// Migrations returns the embedded migrations in version order.
+func Migrations() ([]*Migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := map[int]*Migration{}
+
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := migrationFiles.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := migrations[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			migrations[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("duplicate migration version: %d", version)
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(b)
+		} else {
+			migration.Down = string(b)
+		}
+	}
+
+	sorted := make([]*Migration, 0, len(migrations))
+	for _, migration := range migrations {
+		sorted = append(sorted, migration)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	return sorted, nil
+}
+
+// Migrator applies the embedded migrations to a database, recording the
+// applied versions in the schema_migrations table.
+type Migrator struct {
+	DB     *DB
+	Logger sakila.Logger
+}
+
+// Up applies the pending migrations up to and including the target version,
+// or all pending migrations when the target is zero.
+func (m *Migrator) Up(ctx context.Context, target int) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			continue
+		}
+
+		if target > 0 && status.Version > target {
+			break
+		}
+
+		if err := m.exec(ctx, status.Up); err != nil {
+			return fmt.Errorf("migration %d up: %w", status.Version, err)
+		}
+
+		query := "INSERT INTO " + migrationsTable + " (version, name) VALUES (?, ?)"
+
+		if _, err := m.DB.ExecContext(ctx, query, status.Version, status.Name); err != nil {
+			return err
+		}
+
+		m.logInfo(fmt.Sprintf("applied migration %06d_%s", status.Version, status.Name))
+	}
+
+	return nil
+}
+
+// Down reverts the given number of applied migrations, latest first.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := len(statuses) - 1; i >= 0 && steps > 0; i-- {
+		status := statuses[i]
+		if status.AppliedAt == nil {
+			continue
+		}
+
+		if err := m.exec(ctx, status.Down); err != nil {
+			return fmt.Errorf("migration %d down: %w", status.Version, err)
+		}
+
+		query := "DELETE FROM " + migrationsTable + " WHERE version = ?"
+
+		if _, err := m.DB.ExecContext(ctx, query, status.Version); err != nil {
+			return err
+		}
+
+		m.logInfo(fmt.Sprintf("reverted migration %06d_%s", status.Version, status.Name))
+
+		steps--
+	}
+
+	return nil
+}
+
+// Status returns the status of every embedded migration.
+func (m *Migrator) Status(ctx context.Context) ([]*MigrationStatus, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*MigrationStatus, len(migrations))
+	for i, migration := range migrations {
+		statuses[i] = &MigrationStatus{Migration: migration}
+
+		if appliedAt, ok := applied[migration.Version]; ok {
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+
+	return statuses, nil
+}
+
+// applied returns the application times of the applied migrations, creating
+// the migrations table if needed.
+func (m *Migrator) applied(ctx context.Context) (map[int]time.Time, error) {
+	if _, err := m.DB.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+migrationsTable+` (
+		version BIGINT UNSIGNED NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (version)
+	)`); err != nil {
+		return nil, err
+	}
+
+	query, args := mrqb.Select("version", "applied_at").From(migrationsTable).Build()
+
+	rows, err := m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close() //nolint:errcheck
+
+	applied := map[int]time.Time{}
+
+	for rows.Next() {
+		var version int
+		var appliedAt sql.NullTime
+
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+
+		applied[version] = appliedAt.Time
+	}
+
+	return applied, rows.Err()
+}
+
+// exec executes the statements of a migration script one at a time, since
+// multiple statements per query are disabled by default.
+func (m *Migrator) exec(ctx context.Context, script string) error {
+	for _, statement := range splitStatements(script) {
+		if _, err := m.DB.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Migrator) logInfo(msg string) {
+	if logger := m.Logger; logger != nil {
+		logger.Info(msg)
+	}
+}
+
+// splitStatements splits a SQL script into statements ending with a semicolon
+// at the end of a line.
+func splitStatements(script string) []string {
+	statements := []string{}
+
+	b := strings.Builder{}
+
+	for _, line := range strings.Split(script, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+
+		b.WriteString(line + "\n")
+
+		if strings.HasSuffix(strings.TrimSpace(line), ";") {
+			if statement := strings.TrimSpace(b.String()); statement != ";" {
+				statements = append(statements, strings.TrimSuffix(statement, ";"))
+			}
+
+			b.Reset()
+		}
+	}
+
+	if statement := strings.TrimSpace(b.String()); statement != "" {
+		statements = append(statements, statement)
+	}
+
+	return statements
+}
@@ -0,0 +1,24 @@
+package mysql_test
+
+import (
+	"github.com/nickmro/sakila-service-film/sakila/mysql"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Migrations", func() {
+	It("never drops the baseline tables", func() {
+		migrations, err := mysql.Migrations()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(migrations[0].Version).To(Equal(1))
+		Expect(migrations[0].Down).ToNot(ContainSubstring("DROP"))
+	})
+
+	It("reverts the film translations", func() {
+		migrations, err := mysql.Migrations()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(migrations[1].Version).To(Equal(2))
+		Expect(migrations[1].Down).To(ContainSubstring("DROP TABLE IF EXISTS film_translation"))
+	})
+})
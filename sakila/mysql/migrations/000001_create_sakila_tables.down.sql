@@ -0,0 +1,3 @@
+-- 000001 is the baseline of the Sakila tables. They may predate this service
+-- in a shared database and hold data it did not create, so reverting the
+-- baseline only forgets that it was applied and never drops them.
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS film_translation (
+  film_id SMALLINT UNSIGNED NOT NULL,
+  locale VARCHAR(35) NOT NULL,
+  title VARCHAR(128) NOT NULL,
+  description TEXT DEFAULT NULL,
+  last_update TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (film_id, locale),
+  KEY idx_film_translation_locale (locale),
+  KEY idx_film_translation_last_update (last_update),
+  CONSTRAINT fk_film_translation_film FOREIGN KEY (film_id) REFERENCES film (film_id) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,13 @@
+package mysql_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestMysql(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Mysql Suite")
+}
@@ -0,0 +1,131 @@
+package mysql
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+	"github.com/nickmro/sakila-service-film/sakila/dataset"
+)
+
+// SakilaDataURL is the download URL of the standard Sakila dataset.
+const SakilaDataURL = "https://downloads.mysql.com/docs/sakila-db.tar.gz"
+
+// seedTables are the tables loaded from the standard dataset.
+var seedTables = map[string]bool{
+	"actor":         true,
+	"category":      true,
+	"film":          true,
+	"film_actor":    true,
+	"film_category": true,
+	"film_text":     true,
+	"inventory":     true,
+	"language":      true,
+}
+
+// insertTable matches the table name of an INSERT statement.
+var insertTable = regexp.MustCompile("^INSERT INTO `?(\\w+)`?")
+
+// seedBatchSize is the number of rows per generated INSERT statement.
+const seedBatchSize = 500
+
+// Seeder loads data into the migrated tables.
+type Seeder struct {
+	DB     *DB
+	Logger sakila.Logger
+}
+
+// SeedScript loads the rows inserted by a Sakila data script, such as
+// sakila-data.sql, into the migrated tables. Statements for other tables are
+// skipped, and the film_text table is filled from the films.
+func (s *Seeder) SeedScript(ctx context.Context, script io.Reader) error {
+	return s.withConn(ctx, func(conn *sql.Conn) error {
+		scanner := bufio.NewScanner(script)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+		b := strings.Builder{}
+
+		for scanner.Scan() {
+			line := scanner.Text()
+
+			b.WriteString(line + "\n")
+
+			if !strings.HasSuffix(strings.TrimSpace(line), ";") {
+				continue
+			}
+
+			statement := strings.TrimSpace(b.String())
+			b.Reset()
+
+			if match := insertTable.FindStringSubmatch(statement); match == nil || !seedTables[match[1]] {
+				continue
+			}
+
+			if _, err := conn.ExecContext(ctx, statement); err != nil {
+				return err
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+
+		return s.seedFilmText(ctx, conn)
+	})
+}
+
+// SeedSynthetic loads a generated dataset of the given size.
+func (s *Seeder) SeedSynthetic(ctx context.Context, params dataset.SyntheticParams) error {
+	return s.withConn(ctx, func(conn *sql.Conn) error {
+		for _, table := range dataset.Synthetic(params) {
+			for _, rows := range table.Batches(seedBatchSize) {
+				query, args := table.InsertQuery(rows)
+
+				if _, err := conn.ExecContext(ctx, query, args...); err != nil {
+					return err
+				}
+			}
+		}
+
+		return s.seedFilmText(ctx, conn)
+	})
+}
+
+// withConn runs the seed on a single connection with foreign key checks
+// disabled, so that tables can be loaded in any order.
+func (s *Seeder) withConn(ctx context.Context, seed func(conn *sql.Conn) error) error {
+	conn, err := s.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close() //nolint:errcheck
+
+	if _, err := conn.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS = 0"); err != nil {
+		return err
+	}
+
+	//nolint:errcheck
+	defer conn.ExecContext(context.Background(), "SET FOREIGN_KEY_CHECKS = 1")
+
+	if err := seed(conn); err != nil {
+		return err
+	}
+
+	if logger := s.Logger; logger != nil {
+		logger.Info("seeded database")
+	}
+
+	return nil
+}
+
+func (s *Seeder) seedFilmText(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `INSERT IGNORE INTO film_text (film_id, title, description)
+		SELECT film_id, title, description FROM film`)
+
+	return err
+}
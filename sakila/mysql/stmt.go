@@ -0,0 +1,263 @@
+package mysql
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"expvar"
+	"strings"
+)
+
+// DefaultMaxPreparedStatements is the default number of statements a DB keeps
+// prepared.
+const DefaultMaxPreparedStatements = 256
+
+// maxBucketSize is the size from which lists are no longer padded to a bucket
+// size, so that long lists do not double their number of placeholders.
+const maxBucketSize = 1024
+
+// statementMetrics tracks the prepared statement cache of every DB.
+var statementMetrics = expvar.NewMap("mysql_statements")
+
+// preparedStmt is a cached prepared statement. An evicted statement is closed
+// once the queries using it have started.
+type preparedStmt struct {
+	query   string
+	stmt    *sql.Stmt
+	users   int
+	evicted bool
+}
+
+// PreparedStatements returns the number of statements the DB keeps prepared.
+func (db *DB) PreparedStatements() int {
+	db.stmtMu.Lock()
+	defer db.stmtMu.Unlock()
+
+	return len(db.stmts)
+}
+
+// queryContext runs a query with a cached prepared statement.
+func (db *DB) queryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	prepared, ok := db.stmt(ctx, query)
+	if !ok {
+		return db.DB.QueryContext(ctx, query, args...)
+	}
+
+	defer db.release(prepared)
+
+	return prepared.stmt.QueryContext(ctx, args...)
+}
+
+// queryRowContext runs a single row query with a cached prepared statement.
+func (db *DB) queryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	prepared, ok := db.stmt(ctx, query)
+	if !ok {
+		return db.DB.QueryRowContext(ctx, query, args...)
+	}
+
+	defer db.release(prepared)
+
+	return prepared.stmt.QueryRowContext(ctx, args...)
+}
+
+// stmt returns the prepared statement of the query, preparing it on first
+// use, for use until it is released. Statements are prepared without holding
+// the lock, so that other queries do not wait on the round trip. Queries that
+// fail to prepare are reported as not prepared, so that they run unprepared
+// and surface their own errors.
+func (db *DB) stmt(ctx context.Context, query string) (*preparedStmt, bool) {
+	if prepared, ok := db.cachedStmt(query); ok {
+		statementMetrics.Add("hits", 1)
+		return prepared, true
+	}
+
+	statementMetrics.Add("misses", 1)
+
+	stmt, err := db.DB.PrepareContext(ctx, query)
+	if err != nil {
+		statementMetrics.Add("errors", 1)
+		db.logError(err)
+
+		return nil, false
+	}
+
+	prepared, evicted := db.addStmt(query, stmt)
+
+	for _, e := range evicted {
+		db.closeStmt(e)
+	}
+
+	return prepared, true
+}
+
+// cachedStmt returns the cached statement of the query, marked as used.
+func (db *DB) cachedStmt(query string) (*preparedStmt, bool) {
+	db.stmtMu.Lock()
+	defer db.stmtMu.Unlock()
+
+	element, ok := db.stmts[query]
+	if !ok {
+		return nil, false
+	}
+
+	db.stmtLRU.MoveToFront(element)
+
+	prepared := element.Value.(*preparedStmt)
+	prepared.users++
+
+	return prepared, true
+}
+
+// addStmt caches the statement prepared for the query, marked as used, unless
+// a concurrent query prepared it first. It returns the cached statement and
+// the evicted statements to close.
+func (db *DB) addStmt(query string, stmt *sql.Stmt) (*preparedStmt, []*sql.Stmt) {
+	db.stmtMu.Lock()
+	defer db.stmtMu.Unlock()
+
+	if element, ok := db.stmts[query]; ok {
+		db.stmtLRU.MoveToFront(element)
+
+		prepared := element.Value.(*preparedStmt)
+		prepared.users++
+
+		return prepared, []*sql.Stmt{stmt}
+	}
+
+	if db.stmts == nil {
+		db.stmts = map[string]*list.Element{}
+	}
+
+	prepared := &preparedStmt{query: query, stmt: stmt, users: 1}
+	db.stmts[query] = db.stmtLRU.PushFront(prepared)
+
+	statementMetrics.Add("prepared", 1)
+
+	evicted := []*sql.Stmt{}
+
+	for len(db.stmts) > db.maxPreparedStatements() {
+		oldest := db.stmtLRU.Back().Value.(*preparedStmt)
+
+		db.stmtLRU.Remove(db.stmtLRU.Back())
+		delete(db.stmts, oldest.query)
+
+		statementMetrics.Add("prepared", -1)
+		statementMetrics.Add("evictions", 1)
+
+		oldest.evicted = true
+		if oldest.users == 0 {
+			evicted = append(evicted, oldest.stmt)
+		}
+	}
+
+	return prepared, evicted
+}
+
+// release marks the statement as no longer used by a query, closing it if it
+// was evicted meanwhile. Rows keep their statement open until they are
+// closed.
+func (db *DB) release(prepared *preparedStmt) {
+	db.stmtMu.Lock()
+
+	prepared.users--
+	closing := prepared.evicted && prepared.users == 0
+
+	db.stmtMu.Unlock()
+
+	if closing {
+		db.closeStmt(prepared.stmt)
+	}
+}
+
+func (db *DB) closeStmt(stmt *sql.Stmt) {
+	if err := stmt.Close(); err != nil {
+		db.logError(err)
+	}
+}
+
+// closeStmts closes the prepared statements.
+func (db *DB) closeStmts() error {
+	db.stmtMu.Lock()
+	defer db.stmtMu.Unlock()
+
+	var firstErr error
+
+	for query, element := range db.stmts {
+		if err := element.Value.(*preparedStmt).stmt.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+
+		delete(db.stmts, query)
+		statementMetrics.Add("prepared", -1)
+	}
+
+	db.stmtLRU.Init()
+
+	return firstErr
+}
+
+func (db *DB) maxPreparedStatements() int {
+	if db.MaxPreparedStatements > 0 {
+		return db.MaxPreparedStatements
+	}
+
+	return DefaultMaxPreparedStatements
+}
+
+func (db *DB) logError(err error) {
+	if logger := db.Logger; logger != nil {
+		logger.Error(err)
+	}
+}
+
+// bucketSize returns the size of the list bucket holding n values: the next
+// power of two, up to the maximum bucket size. Lists are padded to their
+// bucket size so that queries with lists of similar lengths share a prepared
+// statement.
+func bucketSize(n int) int {
+	if n > maxBucketSize {
+		return n
+	}
+
+	size := 1
+	for size < n {
+		size *= 2
+	}
+
+	return size
+}
+
+// bucketedIDs returns the IDs padded to their bucket size by repeating the
+// last ID.
+func bucketedIDs(ids []int) []interface{} {
+	values := make([]interface{}, bucketSize(len(ids)))
+	for i := range values {
+		if i < len(ids) {
+			values[i] = ids[i]
+		} else {
+			values[i] = ids[len(ids)-1]
+		}
+	}
+
+	return values
+}
+
+// bucketedStrings returns the values padded to their bucket size by repeating
+// the last value.
+func bucketedStrings(values []string) []interface{} {
+	bucketed := make([]interface{}, bucketSize(len(values)))
+	for i := range bucketed {
+		if i < len(values) {
+			bucketed[i] = values[i]
+		} else {
+			bucketed[i] = values[len(values)-1]
+		}
+	}
+
+	return bucketed
+}
+
+// placeholders returns a comma separated list of n placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
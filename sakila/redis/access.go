@@ -0,0 +1,140 @@
+package redis
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// accessCounts counts the accesses to the members of access sets in process
+// memory, until they are drained into the cache.
+type accessCounts struct {
+	mu     sync.Mutex
+	counts map[string]map[string]float64
+}
+
+func (accesses *accessCounts) add(setKey, member string) {
+	accesses.mu.Lock()
+	defer accesses.mu.Unlock()
+
+	if accesses.counts == nil {
+		accesses.counts = map[string]map[string]float64{}
+	}
+
+	set, ok := accesses.counts[setKey]
+	if !ok {
+		set = map[string]float64{}
+		accesses.counts[setKey] = set
+	}
+
+	set[member]++
+}
+
+// drain returns the access counts by set key, and resets them.
+func (accesses *accessCounts) drain() map[string]map[string]float64 {
+	accesses.mu.Lock()
+	defer accesses.mu.Unlock()
+
+	counts := accesses.counts
+	accesses.counts = nil
+
+	return counts
+}
+
+// localAccesses holds the access sets of a local cache in process memory.
+// Expired sets are removed when they are next read or written.
+type localAccesses struct {
+	mu   sync.Mutex
+	sets map[string]*localAccessSet
+}
+
+type localAccessSet struct {
+	counts  map[string]float64
+	expires time.Time
+}
+
+func newLocalAccesses() *localAccesses {
+	return &localAccesses{
+		sets: map[string]*localAccessSet{},
+	}
+}
+
+// add adds the access counts to the sets, and trims the sets to their size
+// most accessed members.
+func (accesses *localAccesses) add(counts map[string]map[string]float64, size int, ttl time.Duration) {
+	accesses.mu.Lock()
+	defer accesses.mu.Unlock()
+
+	now := time.Now()
+
+	for setKey, members := range counts {
+		set := accesses.set(setKey, now)
+		if set == nil {
+			set = &localAccessSet{counts: map[string]float64{}}
+			accesses.sets[setKey] = set
+		}
+
+		for member, count := range members {
+			set.counts[member] += count
+		}
+
+		if members := set.members(); len(members) > size {
+			for _, member := range members[size:] {
+				delete(set.counts, member)
+			}
+		}
+
+		set.expires = now.Add(ttl)
+	}
+}
+
+// top returns the n most accessed members of the set.
+func (accesses *localAccesses) top(setKey string, n int) []string {
+	accesses.mu.Lock()
+	defer accesses.mu.Unlock()
+
+	set := accesses.set(setKey, time.Now())
+	if set == nil {
+		return []string{}
+	}
+
+	members := set.members()
+	if len(members) > n {
+		members = members[:n]
+	}
+
+	return members
+}
+
+// members returns the members of the set, most accessed first.
+func (set *localAccessSet) members() []string {
+	members := make([]string, 0, len(set.counts))
+	for member := range set.counts {
+		members = append(members, member)
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		if set.counts[members[i]] != set.counts[members[j]] {
+			return set.counts[members[i]] > set.counts[members[j]]
+		}
+
+		return members[i] < members[j]
+	})
+
+	return members
+}
+
+// set returns the unexpired access set, or nil.
+func (accesses *localAccesses) set(setKey string, now time.Time) *localAccessSet {
+	set, ok := accesses.sets[setKey]
+	if !ok {
+		return nil
+	}
+
+	if now.After(set.expires) {
+		delete(accesses.sets, setKey)
+		return nil
+	}
+
+	return set
+}
@@ -2,44 +2,106 @@ package redis
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
+	"github.com/nickmro/sakila-service-film/sakila"
+
 	"github.com/go-redis/cache/v8"
 	"github.com/go-redis/redis/v8"
 )
 
-// Cache is a redis cache.
+// Cache is a redis cache, with an optional in-process cache in front of
+// Redis. Keys deleted from the cache are published on the invalidation
+// channel, so that every service replica evicts them from its in-process
+// cache.
+//
+// The film service caches values encoded with the Codec, msgpack by default,
+// and compressed with the Compression when they reach the
+// CompressionThreshold.
 type Cache struct {
 	*cache.Cache
-	client *redis.Client
+	Codec                Codec
+	Compression          Compression
+	CompressionThreshold int
+
+	client              redis.UniversalClient
+	index               *localIndex
+	accesses            *localAccesses
+	local               bool
+	keyPrefix           string
+	invalidationChannel string
+	logger              sakila.Logger
 }
 
+// DefaultInvalidationChannel is the default pub/sub channel of the keys
+// deleted from the cache.
+const DefaultInvalidationChannel = "cache_invalidations"
+
 const pingTimeoutDuration = time.Second * 10
 
-// NewCache returns a new cache.
+// NewCache returns a new cache backed by a standalone, sentinel or cluster
+// Redis deployment.
 func NewCache(params *ClientParams) (*Cache, error) {
-	client := redis.NewClient(&redis.Options{
-		Addr:     address(params.Host, params.Port),
-		Password: params.Password,
-		DB:       params.DB,
-	})
+	client, err := newClient(params)
+	if err != nil {
+		return nil, err
+	}
 
 	status := client.Ping(context.Background())
 	if err := status.Err(); err != nil {
+		//nolint:errcheck
+		client.Close()
+
 		return nil, err
 	}
 
+	options := &cache.Options{Redis: client}
+
+	if params.LocalCacheSize > 0 {
+		localCacheTTL := params.LocalCacheTTL
+		if localCacheTTL == 0 {
+			localCacheTTL = DefaultTTL
+		}
+
+		options.LocalCache = cache.NewTinyLFU(params.LocalCacheSize, localCacheTTL)
+	}
+
+	invalidationChannel := DefaultInvalidationChannel
+	if prefix := params.KeyPrefix; prefix != "" {
+		invalidationChannel = prefix + "::" + invalidationChannel
+	}
+
+	return &Cache{
+		Cache:               cache.New(options),
+		client:              client,
+		local:               options.LocalCache != nil,
+		keyPrefix:           params.KeyPrefix,
+		invalidationChannel: invalidationChannel,
+		logger:              params.Logger,
+	}, nil
+}
+
+// NewLocalCache returns a cache kept in process memory only, holding up to
+// size items for the TTL. Film events published through a local cache are
+// only delivered within the process.
+func NewLocalCache(size int, ttl time.Duration) *Cache {
 	return &Cache{
 		Cache: cache.New(&cache.Options{
-			Redis:      client,
-			LocalCache: cache.NewTinyLFU(10000, DefaultTTL),
+			LocalCache: cache.NewTinyLFU(size, ttl),
 		}),
-		client: client,
-	}, nil
+		index:    newLocalIndex(),
+		accesses: newLocalAccesses(),
+		local:    true,
+	}
 }
 
 // Status returns the client status.
 func (cache *Cache) Status() (interface{}, error) {
+	if cache.client == nil {
+		return nil, nil
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), pingTimeoutDuration)
 	defer cancel()
 
@@ -48,5 +110,206 @@ func (cache *Cache) Status() (interface{}, error) {
 
 // Close closes the cache client connection.
 func (cache *Cache) Close() error {
+	if cache.client == nil {
+		return nil
+	}
+
 	return cache.client.Close()
 }
+
+// codec returns the codec of the cached values.
+func (cache *Cache) codec() Codec {
+	if codec := cache.Codec; codec != nil {
+		return codec
+	}
+
+	return MsgpackCodec
+}
+
+// compressionThreshold returns the size from which cached values are
+// compressed.
+func (cache *Cache) compressionThreshold() int {
+	if threshold := cache.CompressionThreshold; threshold > 0 {
+		return threshold
+	}
+
+	return DefaultCompressionThreshold
+}
+
+// addToIndexes adds the key to the index sets, which expire after the TTL.
+func (cache *Cache) addToIndexes(ctx context.Context, key string, ttl time.Duration, indexKeys ...string) error {
+	if cache.client == nil {
+		cache.index.add(key, ttl, indexKeys...)
+		return nil
+	}
+
+	_, err := cache.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, indexKey := range indexKeys {
+			pipe.SAdd(ctx, indexKey, key)
+			pipe.Expire(ctx, indexKey, ttl)
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+// indexMembers returns the keys in the index set.
+func (cache *Cache) indexMembers(ctx context.Context, indexKey string) ([]string, error) {
+	if cache.client == nil {
+		return cache.index.members(indexKey), nil
+	}
+
+	return cache.client.SMembers(ctx, indexKey).Result()
+}
+
+// deleteIndex removes the index set.
+func (cache *Cache) deleteIndex(ctx context.Context, indexKey string) error {
+	if cache.client == nil {
+		cache.index.delete(indexKey)
+		return nil
+	}
+
+	return cache.client.Del(ctx, indexKey).Err()
+}
+
+// addAccesses adds the access counts to the access sets, which expire after
+// the TTL, and trims the sets to their size most accessed members.
+func (cache *Cache) addAccesses(
+	ctx context.Context,
+	counts map[string]map[string]float64,
+	size int,
+	ttl time.Duration,
+) error {
+	if cache.client == nil {
+		cache.accesses.add(counts, size, ttl)
+		return nil
+	}
+
+	_, err := cache.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for setKey, members := range counts {
+			for member, count := range members {
+				pipe.ZIncrBy(ctx, setKey, count, member)
+			}
+
+			pipe.ZRemRangeByRank(ctx, setKey, 0, int64(-size-1))
+			pipe.Expire(ctx, setKey, ttl)
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+// topAccesses returns the n most accessed members of the access set.
+func (cache *Cache) topAccesses(ctx context.Context, setKey string, n int) ([]string, error) {
+	if n <= 0 {
+		return []string{}, nil
+	}
+
+	if cache.client == nil {
+		return cache.accesses.top(setKey, n), nil
+	}
+
+	return cache.client.ZRevRange(ctx, setKey, 0, int64(n-1)).Result()
+}
+
+// deleteBatchSize is the number of keys deleted in a single pipeline, and
+// published in a single invalidation message.
+const deleteBatchSize = 1000
+
+// delete removes the keys from the cache, and publishes them on the
+// invalidation channel for the in-process caches of the service replicas.
+func (cache *Cache) delete(ctx context.Context, keys ...string) error {
+	for start := 0; start < len(keys); start += deleteBatchSize {
+		end := start + deleteBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		if err := cache.deleteBatch(ctx, keys[start:end]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteBatch removes the keys from the cache in a single pipeline, and
+// publishes them in a single invalidation message.
+func (cache *Cache) deleteBatch(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		cache.DeleteFromLocalCache(key)
+	}
+
+	if cache.client == nil {
+		return nil
+	}
+
+	_, err := cache.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Del(ctx, key)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+
+	return cache.client.Publish(ctx, cache.invalidationChannel, b).Err()
+}
+
+// ListenInvalidations evicts the keys received on the invalidation channel
+// from the in-process cache until the context is done. It returns
+// immediately when the cache has no in-process cache in front of Redis.
+func (cache *Cache) ListenInvalidations(ctx context.Context) error {
+	if cache.client == nil || !cache.local {
+		return nil
+	}
+
+	pubSub := cache.client.Subscribe(ctx, cache.invalidationChannel)
+
+	//nolint:errcheck
+	defer pubSub.Close()
+
+	if _, err := pubSub.Receive(ctx); err != nil {
+		return err
+	}
+
+	messages := pubSub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message, ok := <-messages:
+			if !ok {
+				return nil
+			}
+
+			var keys []string
+			if err := json.Unmarshal([]byte(message.Payload), &keys); err != nil {
+				cache.logError(err)
+				continue
+			}
+
+			for _, key := range keys {
+				cache.DeleteFromLocalCache(key)
+			}
+		}
+	}
+}
+
+func (cache *Cache) logError(err error) {
+	if logger := cache.logger; logger != nil {
+		logger.Error(err)
+	}
+}
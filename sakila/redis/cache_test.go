@@ -0,0 +1,35 @@
+package redis_test
+
+import (
+	"context"
+
+	"github.com/nickmro/sakila-service-film/sakila/redis"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cache", func() {
+	Describe("NewCache", func() {
+		It("returns an error for an unknown mode", func() {
+			cache, err := redis.NewCache(&redis.ClientParams{Mode: "replicated", Host: "localhost", Port: 6379})
+			Expect(err).To(MatchError(`unknown redis mode "replicated"`))
+			Expect(cache).To(BeNil())
+		})
+	})
+
+	Describe("ListenInvalidations", func() {
+		It("returns immediately for a local cache", func() {
+			cache := redis.NewLocalCache(1000, redis.DefaultTTL)
+			Expect(cache.ListenInvalidations(context.Background())).To(Succeed())
+		})
+	})
+
+	Describe("Keys", func() {
+		It("returns an error for a local cache", func() {
+			cache := redis.NewLocalCache(1000, redis.DefaultTTL)
+			_, err := cache.Keys(context.Background(), "*")
+			Expect(err).To(MatchError(redis.ErrorNoRedis))
+		})
+	})
+})
@@ -0,0 +1,135 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+)
+
+// Service returns the cached film service exposing the optional lookups of
+// the wrapped film service: it implements sakila.ActorService,
+// sakila.FilmStoreService and sakila.FilmTranslationService only when the
+// wrapped film service does, so that the GraphQL schema and loaders are built
+// for the lookups that can be resolved. The service itself implements none of
+// them.
+func (service *FilmService) Service() sakila.FilmService {
+	actorService, actors := service.FilmService.(sakila.ActorService)
+	storeService, stores := service.FilmService.(sakila.FilmStoreService)
+	translationService, translations := service.FilmService.(sakila.FilmTranslationService)
+
+	return capabilityServices[capabilities{
+		actors:       actors,
+		stores:       stores,
+		translations: translations,
+	}](service, caches{
+		actors:       actorCache{service: service, actorService: actorService},
+		stores:       filmStoreCache{service: service, storeService: storeService},
+		translations: filmTranslationCache{service: service, translationService: translationService},
+	})
+}
+
+// capabilities are the optional lookups of a film service.
+type capabilities struct {
+	actors       bool
+	stores       bool
+	translations bool
+}
+
+// caches are the caches of the optional lookups of a film service.
+type caches struct {
+	actors       actorCache
+	stores       filmStoreCache
+	translations filmTranslationCache
+}
+
+// capabilityServices return the cached film service of each capability set,
+// with one type per set implementing exactly its optional interfaces.
+var capabilityServices = map[capabilities]func(service *FilmService, c caches) sakila.FilmService{
+	{}: func(service *FilmService, _ caches) sakila.FilmService {
+		return service
+	},
+	{actors: true}: func(service *FilmService, c caches) sakila.FilmService {
+		return &struct {
+			*FilmService
+			actorCache
+		}{service, c.actors}
+	},
+	{stores: true}: func(service *FilmService, c caches) sakila.FilmService {
+		return &struct {
+			*FilmService
+			filmStoreCache
+		}{service, c.stores}
+	},
+	{translations: true}: func(service *FilmService, c caches) sakila.FilmService {
+		return &struct {
+			*FilmService
+			filmTranslationCache
+		}{service, c.translations}
+	},
+	{actors: true, stores: true}: func(service *FilmService, c caches) sakila.FilmService {
+		return &struct {
+			*FilmService
+			actorCache
+			filmStoreCache
+		}{service, c.actors, c.stores}
+	},
+	{actors: true, translations: true}: func(service *FilmService, c caches) sakila.FilmService {
+		return &struct {
+			*FilmService
+			actorCache
+			filmTranslationCache
+		}{service, c.actors, c.translations}
+	},
+	{stores: true, translations: true}: func(service *FilmService, c caches) sakila.FilmService {
+		return &struct {
+			*FilmService
+			filmStoreCache
+			filmTranslationCache
+		}{service, c.stores, c.translations}
+	},
+	{actors: true, stores: true, translations: true}: func(service *FilmService, c caches) sakila.FilmService {
+		return &struct {
+			*FilmService
+			actorCache
+			filmStoreCache
+			filmTranslationCache
+		}{service, c.actors, c.stores, c.translations}
+	},
+}
+
+// actorCache caches the actor lookups of a film service.
+type actorCache struct {
+	service      *FilmService
+	actorService sakila.ActorService
+}
+
+// GetActors returns actors from the cache.
+func (c actorCache) GetActors(ctx context.Context, actorIDs ...int) ([]*sakila.Actor, error) {
+	return c.service.getActors(ctx, c.actorService, actorIDs...)
+}
+
+// filmStoreCache caches the film store lookups of a film service.
+type filmStoreCache struct {
+	service      *FilmService
+	storeService sakila.FilmStoreService
+}
+
+// GetFilmStores returns film stores from the cache.
+func (c filmStoreCache) GetFilmStores(ctx context.Context, filmIDs ...int) ([]*sakila.FilmStore, error) {
+	return c.service.getFilmStores(ctx, c.storeService, filmIDs...)
+}
+
+// filmTranslationCache caches the film translation lookups of a film service.
+type filmTranslationCache struct {
+	service            *FilmService
+	translationService sakila.FilmTranslationService
+}
+
+// GetFilmTranslations returns film translations from the cache.
+func (c filmTranslationCache) GetFilmTranslations(
+	ctx context.Context,
+	locale string,
+	filmIDs ...int,
+) ([]*sakila.FilmTranslation, error) {
+	return c.service.getFilmTranslations(ctx, c.translationService, locale, filmIDs...)
+}
@@ -1,13 +1,89 @@
 package redis
 
-import "fmt"
+import (
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// The Redis deployment modes.
+const (
+	ModeStandalone = "standalone"
+	ModeSentinel   = "sentinel"
+	ModeCluster    = "cluster"
+)
 
 // ClientParams are Redis client parameters.
 type ClientParams struct {
-	Host     string
-	Port     int
-	Password string
-	DB       int
+	// Mode is the deployment mode, standalone by default.
+	Mode string
+	// Addrs are the server addresses: the server, the sentinels or the
+	// cluster nodes. The Host and Port address is used when empty.
+	Addrs []string
+	Host  string
+	Port  int
+	// MasterName is the name of the master monitored by the sentinels.
+	MasterName       string
+	Username         string
+	Password         string
+	SentinelPassword string
+	DB               int
+	TLSConfig        *tls.Config
+	PoolSize         int
+	MinIdleConns     int
+	PoolTimeout      time.Duration
+	DialTimeout      time.Duration
+	ReadTimeout      time.Duration
+	WriteTimeout     time.Duration
+
+	// LocalCacheSize and LocalCacheTTL bound the in-process cache in front of
+	// Redis, which is disabled when the size is zero. The TTL defaults to
+	// DefaultTTL.
+	LocalCacheSize int
+	LocalCacheTTL  time.Duration
+
+	// KeyPrefix prefixes the cache invalidation channel.
+	KeyPrefix string
+	Logger    sakila.Logger
+}
+
+// newClient returns a client for the deployment mode of the parameters.
+func newClient(params *ClientParams) (redis.UniversalClient, error) {
+	addrs := params.Addrs
+	if len(addrs) == 0 {
+		addrs = []string{address(params.Host, params.Port)}
+	}
+
+	options := &redis.UniversalOptions{
+		Addrs:            addrs,
+		MasterName:       params.MasterName,
+		Username:         params.Username,
+		Password:         params.Password,
+		SentinelPassword: params.SentinelPassword,
+		DB:               params.DB,
+		TLSConfig:        params.TLSConfig,
+		PoolSize:         params.PoolSize,
+		MinIdleConns:     params.MinIdleConns,
+		PoolTimeout:      params.PoolTimeout,
+		DialTimeout:      params.DialTimeout,
+		ReadTimeout:      params.ReadTimeout,
+		WriteTimeout:     params.WriteTimeout,
+	}
+
+	switch params.Mode {
+	case "", ModeStandalone:
+		return redis.NewClient(options.Simple()), nil
+	case ModeSentinel:
+		return redis.NewFailoverClient(options.Failover()), nil
+	case ModeCluster:
+		return redis.NewClusterClient(options.Cluster()), nil
+	default:
+		return nil, fmt.Errorf("unknown redis mode %q", params.Mode)
+	}
 }
 
 func address(host string, port int) string {
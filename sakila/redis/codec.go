@@ -0,0 +1,277 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Codec encodes the cached values. Codecs are registered under the ID
+// recorded in the entries they encode, so that entries are decoded after the
+// codec of the cache changes.
+type Codec interface {
+	Name() string
+	Marshal(value interface{}) ([]byte, error)
+	Unmarshal(b []byte, value interface{}) error
+}
+
+// The codecs registered by the package.
+var (
+	// MsgpackCodec encodes values with msgpack.
+	MsgpackCodec Codec = msgpackCodec{}
+	// JSONCodec encodes values with their JSON encoding.
+	JSONCodec Codec = jsonCodec{}
+	// ProtobufCodec encodes the JSON encoding of values as a protobuf
+	// google.protobuf.Value message, for readers of the cache in other
+	// languages. Values decode as they do with the JSON codec.
+	ProtobufCodec Codec = protobufCodec{}
+)
+
+// The IDs of the codecs registered by the package.
+const (
+	codecIDMsgpack byte = iota + 1
+	codecIDJSON
+	codecIDProtobuf
+)
+
+var codecsMu sync.RWMutex
+
+// codecs are the registered codecs by ID.
+var codecs = map[byte]Codec{
+	codecIDMsgpack:  MsgpackCodec,
+	codecIDJSON:     JSONCodec,
+	codecIDProtobuf: ProtobufCodec,
+}
+
+// RegisterCodec registers a codec under the ID recorded in the entries it
+// encodes, so that caches can encode values with it. IDs and names must be
+// unique, and IDs must never be reused for another codec while entries it
+// encoded are cached.
+func RegisterCodec(id byte, codec Codec) error {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+
+	if id == 0 {
+		return fmt.Errorf("invalid cache codec ID %d", id)
+	}
+
+	if registered, ok := codecs[id]; ok {
+		return fmt.Errorf("cache codec ID %d is registered to %q", id, registered.Name())
+	}
+
+	for _, registered := range codecs {
+		if registered.Name() == codec.Name() {
+			return fmt.Errorf("cache codec %q is already registered", codec.Name())
+		}
+	}
+
+	codecs[id] = codec
+
+	return nil
+}
+
+// CodecByName returns the registered codec with the name, such as msgpack,
+// json or protobuf.
+func CodecByName(name string) (Codec, error) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	for _, codec := range codecs {
+		if codec.Name() == name {
+			return codec, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown cache codec %q", name)
+}
+
+// codecID returns the ID of the codec recorded in entries.
+func codecID(codec Codec) (byte, error) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	for id, registered := range codecs {
+		if registered.Name() == codec.Name() {
+			return id, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unregistered cache codec %q", codec.Name())
+}
+
+// codecByID returns the codec of the ID recorded in an entry.
+func codecByID(id byte) (Codec, error) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	codec, ok := codecs[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown cache codec ID %d", id)
+	}
+
+	return codec, nil
+}
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string {
+	return "msgpack"
+}
+
+func (msgpackCodec) Marshal(value interface{}) ([]byte, error) {
+	return msgpack.Marshal(value)
+}
+
+func (msgpackCodec) Unmarshal(b []byte, value interface{}) error {
+	return msgpack.Unmarshal(b, value)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+func (jsonCodec) Marshal(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (jsonCodec) Unmarshal(b []byte, value interface{}) error {
+	return json.Unmarshal(b, value)
+}
+
+type protobufCodec struct{}
+
+func (protobufCodec) Name() string {
+	return "protobuf"
+}
+
+func (protobufCodec) Marshal(value interface{}) ([]byte, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+
+	message, err := structpb.NewValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(message)
+}
+
+func (protobufCodec) Unmarshal(b []byte, value interface{}) error {
+	var message structpb.Value
+	if err := proto.Unmarshal(b, &message); err != nil {
+		return err
+	}
+
+	jsonValue, err := json.Marshal(message.AsInterface())
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(jsonValue, value)
+}
+
+// Compression is the compression of the cached values.
+type Compression string
+
+// The compressions.
+const (
+	CompressionNone = Compression("none")
+	CompressionS2   = Compression("s2")
+	CompressionZstd = Compression("zstd")
+)
+
+// DefaultCompressionThreshold is the default size, in bytes, from which cached
+// values are compressed.
+const DefaultCompressionThreshold = 1024
+
+var compressions = []Compression{CompressionNone, CompressionS2, CompressionZstd}
+
+// The IDs of the compressions recorded in entries.
+const (
+	compressionIDNone byte = iota
+	compressionIDS2
+	compressionIDZstd
+)
+
+// ParseCompression returns the compression with the name: none, s2 or zstd.
+func ParseCompression(name string) (Compression, error) {
+	for _, compression := range compressions {
+		if string(compression) == name {
+			return compression, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown cache compression %q", name)
+}
+
+var zstdOnce sync.Once
+var zstdEncoder *zstd.Encoder
+var zstdDecoder *zstd.Decoder
+var zstdErr error
+
+// zstdCodec returns the zstd encoder and decoder, which are safe for
+// concurrent use.
+func zstdCodec() (*zstd.Encoder, *zstd.Decoder, error) {
+	zstdOnce.Do(func() {
+		if zstdEncoder, zstdErr = zstd.NewWriter(nil); zstdErr != nil {
+			return
+		}
+
+		zstdDecoder, zstdErr = zstd.NewReader(nil)
+	})
+
+	return zstdEncoder, zstdDecoder, zstdErr
+}
+
+// compress returns the compressed bytes, with the ID of the compression.
+func compress(compression Compression, b []byte) (byte, []byte, error) {
+	switch compression {
+	case CompressionS2:
+		return compressionIDS2, s2.Encode(nil, b), nil
+	case CompressionZstd:
+		encoder, _, err := zstdCodec()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		return compressionIDZstd, encoder.EncodeAll(b, nil), nil
+	case CompressionNone, "":
+		return compressionIDNone, b, nil
+	default:
+		return 0, nil, fmt.Errorf("unknown cache compression %q", compression)
+	}
+}
+
+// decompress returns the bytes compressed with the compression of the ID.
+func decompress(id byte, b []byte) ([]byte, error) {
+	switch id {
+	case compressionIDNone:
+		return b, nil
+	case compressionIDS2:
+		return s2.Decode(nil, b)
+	case compressionIDZstd:
+		_, decoder, err := zstdCodec()
+		if err != nil {
+			return nil, err
+		}
+
+		return decoder.DecodeAll(b, nil)
+	default:
+		return nil, fmt.Errorf("unknown cache compression ID %d", id)
+	}
+}
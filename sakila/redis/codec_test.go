@@ -0,0 +1,145 @@
+package redis_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+	"github.com/nickmro/sakila-service-film/sakila/memory"
+	"github.com/nickmro/sakila-service-film/sakila/redis"
+	"github.com/nickmro/sakila-service-film/sakila/sakilatest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Codec", func() {
+	var fixture *memory.Fixture
+
+	BeforeEach(func() {
+		f, err := memory.ReadFixture(strings.NewReader(sakilatest.FixtureJSON))
+		Expect(err).ToNot(HaveOccurred())
+		fixture = f
+	})
+
+	DescribeTable("encodes films",
+		func(name string) {
+			codec, err := redis.CodecByName(name)
+			Expect(err).ToNot(HaveOccurred())
+
+			film, err := memory.NewFilmService(fixture).GetFilm(context.Background(), 1)
+			Expect(err).ToNot(HaveOccurred())
+
+			b, err := codec.Marshal(film)
+			Expect(err).ToNot(HaveOccurred())
+
+			var decoded sakila.Film
+			Expect(codec.Unmarshal(b, &decoded)).To(Succeed())
+			Expect(decoded.Title).To(Equal(film.Title))
+			Expect(decoded.RentalRate).To(Equal(film.RentalRate))
+			Expect(decoded.LastUpdate.Equal(film.LastUpdate)).To(BeTrue())
+		},
+		Entry("msgpack", "msgpack"),
+		Entry("json", "json"),
+		Entry("protobuf", "protobuf"),
+	)
+
+	It("returns an error for an unknown codec", func() {
+		_, err := redis.CodecByName("xml")
+		Expect(err).To(MatchError(`unknown cache codec "xml"`))
+	})
+
+	It("caches values with a registered codec", func() {
+		Expect(redis.RegisterCodec(200, upperJSONCodec{})).To(Succeed())
+
+		cache := redis.NewLocalCache(1000, redis.DefaultTTL)
+		cache.Codec = upperJSONCodec{}
+
+		service := &redis.FilmService{
+			FilmService: memory.NewFilmService(fixture),
+			Cache:       cache,
+		}
+
+		for i := 0; i < 2; i++ {
+			film, err := service.GetFilm(context.Background(), 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(film.Title).To(Equal("ACADEMY DINOSAUR"))
+		}
+	})
+
+	It("rejects a codec registered under a used ID or name", func() {
+		Expect(redis.RegisterCodec(1, upperJSONCodec{})).To(MatchError(`cache codec ID 1 is registered to "msgpack"`))
+		Expect(redis.RegisterCodec(0, upperJSONCodec{})).To(MatchError("invalid cache codec ID 0"))
+		Expect(redis.RegisterCodec(201, redis.JSONCodec)).To(MatchError(`cache codec "json" is already registered`))
+	})
+
+	Context("with a JSON codec and zstd compression", func() {
+		sakilatest.DescribeFilmService(func() sakila.FilmService {
+			cache := redis.NewLocalCache(1000, redis.DefaultTTL)
+			cache.Codec = redis.JSONCodec
+			cache.Compression = redis.CompressionZstd
+			cache.CompressionThreshold = 1
+
+			return (&redis.FilmService{
+				FilmService: memory.NewFilmService(fixture),
+				Cache:       cache,
+			}).Service()
+		})
+	})
+
+	Context("with a protobuf codec and s2 compression", func() {
+		sakilatest.DescribeFilmService(func() sakila.FilmService {
+			cache := redis.NewLocalCache(1000, redis.DefaultTTL)
+			cache.Codec = redis.ProtobufCodec
+			cache.Compression = redis.CompressionS2
+			cache.CompressionThreshold = 1
+
+			return (&redis.FilmService{
+				FilmService: memory.NewFilmService(fixture),
+				Cache:       cache,
+			}).Service()
+		})
+	})
+
+	It("decodes the films cached with another codec", func() {
+		ctx := context.Background()
+		backend := newCountingFilmService()
+
+		cache := redis.NewLocalCache(1000, redis.DefaultTTL)
+		cache.Compression = redis.CompressionS2
+		cache.CompressionThreshold = 1
+
+		service := &redis.FilmService{
+			FilmService: backend,
+			Cache:       cache,
+		}
+
+		_, err := service.GetFilm(ctx, 1)
+		Expect(err).ToNot(HaveOccurred())
+
+		cache.Codec = redis.JSONCodec
+		cache.Compression = redis.CompressionNone
+
+		film, err := service.GetFilm(ctx, 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(film.Title).To(Equal("ACADEMY DINOSAUR"))
+		Expect(backend.Calls()).To(Equal(1))
+	})
+})
+
+// upperJSONCodec is a codec registered by the specs.
+type upperJSONCodec struct{}
+
+func (upperJSONCodec) Name() string {
+	return "upper_json"
+}
+
+func (upperJSONCodec) Marshal(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (upperJSONCodec) Unmarshal(b []byte, value interface{}) error {
+	return json.Unmarshal(b, value)
+}
@@ -0,0 +1,106 @@
+package redis_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+	"github.com/nickmro/sakila-service-film/sakila/memory"
+	"github.com/nickmro/sakila-service-film/sakila/sakilatest"
+
+	. "github.com/onsi/gomega"
+)
+
+// countingFilmService is a memory film service loaded with the fixture that
+// counts the film, film list and film actor lookups reaching it.
+type countingFilmService struct {
+	*memory.FilmService
+
+	mu      sync.Mutex
+	filmIDs []int
+	lists   int
+	actors  int
+}
+
+// newCountingFilmService returns a counting film service loaded with the
+// fixture.
+func newCountingFilmService() *countingFilmService {
+	fixture, err := memory.ReadFixture(strings.NewReader(sakilatest.FixtureJSON))
+	Expect(err).ToNot(HaveOccurred())
+
+	return &countingFilmService{FilmService: memory.NewFilmService(fixture)}
+}
+
+// GetFilm returns the film, counting the lookup.
+func (service *countingFilmService) GetFilm(ctx context.Context, filmID int) (*sakila.Film, error) {
+	service.mu.Lock()
+	service.filmIDs = append(service.filmIDs, filmID)
+	service.mu.Unlock()
+
+	return service.FilmService.GetFilm(ctx, filmID)
+}
+
+// GetFilms returns the films, counting the lookup.
+func (service *countingFilmService) GetFilms(ctx context.Context, params sakila.FilmParams) ([]*sakila.Film, error) {
+	service.mu.Lock()
+	service.lists++
+	service.mu.Unlock()
+
+	return service.FilmService.GetFilms(ctx, params)
+}
+
+// GetFilmActors returns the film actors, counting the lookup.
+func (service *countingFilmService) GetFilmActors(ctx context.Context, filmIDs ...int) ([]*sakila.FilmActor, error) {
+	service.mu.Lock()
+	service.actors++
+	service.mu.Unlock()
+
+	return service.FilmService.GetFilmActors(ctx, filmIDs...)
+}
+
+// Calls returns the number of film, film list and film actor lookups.
+func (service *countingFilmService) Calls() int {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	return len(service.filmIDs) + service.lists + service.actors
+}
+
+// FilmIDs returns the IDs of the film lookups, in order.
+func (service *countingFilmService) FilmIDs() []int {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	return append([]int{}, service.filmIDs...)
+}
+
+// Lists returns the number of film list lookups.
+func (service *countingFilmService) Lists() int {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	return service.lists
+}
+
+// Reset resets the counts.
+func (service *countingFilmService) Reset() {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	service.filmIDs = nil
+	service.lists = 0
+	service.actors = 0
+}
+
+// actorCountingFilmService is a memory film service counting the actor
+// lookups reaching it.
+type actorCountingFilmService struct {
+	*memory.FilmService
+	calls int
+}
+
+func (service *actorCountingFilmService) GetActors(ctx context.Context, actorIDs ...int) ([]*sakila.Actor, error) {
+	service.calls++
+	return service.FilmService.GetActors(ctx, actorIDs...)
+}
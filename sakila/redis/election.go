@@ -0,0 +1,138 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Election elects a single service replica to run a task, such as the change
+// feed, through a lease key in Redis that the leader renews while the task
+// runs. The other replicas retry to take the lease when it expires. A task of
+// a local cache always runs.
+type Election struct {
+	Cache *Cache
+	// Key is the lease key, prefixed with the key prefix of the cache.
+	Key string
+	// TTL is the lease TTL: the longest time the task stops running after its
+	// leader stops.
+	TTL    time.Duration
+	Logger sakila.Logger
+}
+
+// DefaultElectionTTL is the default lease TTL.
+const DefaultElectionTTL = time.Second * 15
+
+var renewLeaseScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return 0`)
+
+var releaseLeaseScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0`)
+
+// Run runs the task whenever the replica holds the lease, until the context is
+// done. The context of the task is canceled when the lease is lost.
+func (election *Election) Run(ctx context.Context, task func(ctx context.Context) error) error {
+	if election.Cache.client == nil {
+		return task(ctx)
+	}
+
+	id, err := leaseID()
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(election.ttl() / 3)
+	defer ticker.Stop()
+
+	for {
+		acquired, err := election.Cache.client.SetNX(ctx, election.key(), id, election.ttl()).Result()
+		if err != nil {
+			election.logError(err)
+		} else if acquired {
+			if err := election.lead(ctx, id, task); err != nil {
+				election.logError(err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// lead runs the task while renewing the lease, and releases the lease when the
+// task returns.
+func (election *Election) lead(ctx context.Context, id string, task func(ctx context.Context) error) error {
+	taskCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- task(taskCtx)
+	}()
+
+	ticker := time.NewTicker(election.ttl() / 3)
+	defer ticker.Stop()
+
+	keys := []string{election.key()}
+
+	for {
+		select {
+		case err := <-done:
+			//nolint:errcheck
+			releaseLeaseScript.Run(context.Background(), election.Cache.client, keys, id)
+
+			return err
+		case <-ticker.C:
+			renewed, err := renewLeaseScript.Run(ctx, election.Cache.client, keys, id, election.ttl().Milliseconds()).Int()
+			if err != nil || renewed == 0 {
+				cancel()
+				<-done
+
+				return err
+			}
+		}
+	}
+}
+
+func (election *Election) key() string {
+	return election.Cache.prefixedKey(election.Key)
+}
+
+func (election *Election) ttl() time.Duration {
+	if election.TTL > 0 {
+		return election.TTL
+	}
+
+	return DefaultElectionTTL
+}
+
+func (election *Election) logError(err error) {
+	if logger := election.Logger; logger != nil {
+		logger.Error(err)
+	}
+}
+
+// leaseID returns a random lease holder ID.
+func leaseID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
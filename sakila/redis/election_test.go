@@ -0,0 +1,125 @@
+package redis_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/nickmro/sakila-service-film/sakila/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Election", func() {
+	Describe("Run", func() {
+		It("runs the task of a local cache", func() {
+			election := &redis.Election{
+				Cache: redis.NewLocalCache(1000, redis.DefaultTTL),
+				Key:   "change_feed::leader",
+			}
+
+			ran := false
+
+			Expect(election.Run(context.Background(), func(ctx context.Context) error {
+				ran = true
+				return nil
+			})).To(Succeed())
+
+			Expect(ran).To(BeTrue())
+		})
+
+		Context("with Redis", func() {
+			var server *miniredis.Miniredis
+			var ctx context.Context
+			var cancel context.CancelFunc
+			var stopped []chan struct{}
+
+			newElection := func() *redis.Election {
+				cache, err := redis.NewCache(&redis.ClientParams{Addrs: []string{server.Addr()}})
+				Expect(err).ToNot(HaveOccurred())
+
+				return &redis.Election{
+					Cache: cache,
+					Key:   "change_feed::leader",
+					TTL:   time.Millisecond * 30,
+				}
+			}
+
+			run := func(election *redis.Election, task func(ctx context.Context) error) {
+				done := make(chan struct{})
+				stopped = append(stopped, done)
+
+				go func() {
+					defer GinkgoRecover()
+					defer close(done)
+
+					Expect(election.Run(ctx, task)).To(Succeed())
+				}()
+			}
+
+			BeforeEach(func() {
+				s, err := miniredis.Run()
+				Expect(err).ToNot(HaveOccurred())
+				server = s
+
+				ctx, cancel = context.WithCancel(context.Background())
+				stopped = nil
+			})
+
+			AfterEach(func() {
+				cancel()
+
+				for _, done := range stopped {
+					Eventually(done).Should(BeClosed())
+				}
+
+				server.Close()
+			})
+
+			It("does not run the task of another election while the lease is held", func() {
+				leading := make(chan struct{})
+
+				run(newElection(), func(ctx context.Context) error {
+					close(leading)
+					<-ctx.Done()
+
+					return nil
+				})
+
+				Eventually(leading).Should(BeClosed())
+
+				following := make(chan struct{})
+
+				run(newElection(), func(ctx context.Context) error {
+					close(following)
+					<-ctx.Done()
+
+					return nil
+				})
+
+				Consistently(following, time.Millisecond*200).ShouldNot(BeClosed())
+			})
+
+			It("cancels the task once the lease cannot be renewed", func() {
+				leading := make(chan struct{})
+				canceled := make(chan struct{})
+
+				run(newElection(), func(ctx context.Context) error {
+					close(leading)
+					<-ctx.Done()
+					close(canceled)
+
+					return nil
+				})
+
+				Eventually(leading).Should(BeClosed())
+				Consistently(canceled, time.Millisecond*100).ShouldNot(BeClosed())
+
+				Expect(server.Set("change_feed::leader", "another replica")).To(Succeed())
+
+				Eventually(canceled).Should(BeClosed())
+			})
+		})
+	})
+})
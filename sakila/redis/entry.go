@@ -0,0 +1,272 @@
+package redis
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+
+	"github.com/go-redis/cache/v8"
+)
+
+// entry is a cached item, encoded with the codec. It is fresh until its soft
+// expiration, after which it is served stale until the cache drops it while
+// it is refreshed. A not found entry caches a sakila.ErrorNotFound result.
+type entry struct {
+	Codec      Codec
+	Value      []byte
+	NotFound   bool
+	FreshUntil time.Time
+}
+
+// loadFunc loads an item from the film service.
+type loadFunc func(ctx context.Context) (interface{}, error)
+
+// SchemaVersion is the version of the cached types, such as sakila.Film. It
+// must be bumped on incompatible changes to the types, so that the entries of
+// other versions are treated as misses.
+const SchemaVersion = 1
+
+// Entries are cached in an envelope made of a header followed by the
+// compressed value:
+//
+//	byte 0      the envelope version
+//	byte 1      the codec ID
+//	byte 2      the compression ID
+//	bytes 3-4   the schema version
+//	byte 5      the flags, 1 for not found entries
+//	bytes 6-13  the end of freshness, in Unix nanoseconds
+const (
+	envelopeVersion      = 1
+	envelopeHeaderSize   = 14
+	envelopeFlagNotFound = 1
+)
+
+// errOutdatedEntry is the error of entries cached in another envelope or
+// schema version.
+var errOutdatedEntry = errors.New("outdated cache entry")
+
+const refreshTimeoutDuration = time.Second * 30
+
+// get reads the cached item of the key into the value, loading it on a miss.
+// Stale items are served while a single goroutine refreshes them, or are
+// loaded again when the service serves no stale items.
+func (service *FilmService) get(
+	ctx context.Context,
+	key string,
+	value interface{},
+	operationTTL time.Duration,
+	load loadFunc,
+) error {
+	var b []byte
+
+	ttl := service.itemTTL(operationTTL)
+
+	item := &cache.Item{
+		Ctx:   ctx,
+		Key:   key,
+		Value: &b,
+		Do: func(i *cache.Item) (interface{}, error) {
+			loaded, hardTTL, err := service.load(ctx, ttl, load)
+			if err != nil {
+				return nil, err
+			}
+
+			i.TTL = hardTTL
+
+			return service.Cache.encode(loaded)
+		},
+		TTL: ttl,
+	}
+
+	if err := service.Cache.Once(item); err != nil {
+		return err
+	}
+
+	// Undecodable entries are treated as misses, and loaded again.
+	e, err := service.Cache.decode(b)
+	if err != nil {
+		if !errors.Is(err, errOutdatedEntry) {
+			service.logError(err)
+		}
+
+		return service.refresh(ctx, key, value, ttl, load)
+	}
+
+	if time.Now().After(e.FreshUntil) {
+		if service.StaleTTL <= 0 {
+			return service.refresh(ctx, key, value, ttl, load)
+		}
+
+		service.refreshInBackground(key, ttl, load)
+	}
+
+	err = service.decode(e, value)
+	if err != nil && !errors.Is(err, sakila.ErrorNotFound) {
+		service.logError(err)
+		return service.refresh(ctx, key, value, ttl, load)
+	}
+
+	return err
+}
+
+// load loads an item into a new entry, fresh for the TTL, and returns the
+// entry with the TTL of the cached entry.
+func (service *FilmService) load(ctx context.Context, ttl time.Duration, load loadFunc) (*entry, time.Duration, error) {
+	value, err := load(ctx)
+	if errors.Is(err, sakila.ErrorNotFound) && service.NegativeTTL > 0 {
+		return &entry{
+			NotFound:   true,
+			FreshUntil: time.Now().Add(service.NegativeTTL),
+		}, service.NegativeTTL, nil
+	} else if err != nil {
+		return nil, 0, err
+	}
+
+	codec := service.Cache.codec()
+
+	b, err := codec.Marshal(value)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &entry{
+		Codec:      codec,
+		Value:      b,
+		FreshUntil: time.Now().Add(ttl),
+	}, ttl + service.StaleTTL, nil
+}
+
+// refresh loads an item again, caches it, and reads it into the value.
+func (service *FilmService) refresh(
+	ctx context.Context,
+	key string,
+	value interface{},
+	ttl time.Duration,
+	load loadFunc,
+) error {
+	e, err := service.store(ctx, key, ttl, load)
+	if err != nil {
+		return err
+	}
+
+	return service.decode(e, value)
+}
+
+// refreshInBackground refreshes a stale item, unless it is already being
+// refreshed.
+func (service *FilmService) refreshInBackground(key string, ttl time.Duration, load loadFunc) {
+	if _, refreshing := service.refreshing.LoadOrStore(key, true); refreshing {
+		return
+	}
+
+	go func() {
+		defer service.refreshing.Delete(key)
+
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeoutDuration)
+		defer cancel()
+
+		if _, err := service.store(ctx, key, ttl, load); err != nil {
+			service.logError(err)
+		}
+	}()
+}
+
+// store loads an item and caches it.
+func (service *FilmService) store(ctx context.Context, key string, ttl time.Duration, load loadFunc) (*entry, error) {
+	e, hardTTL, err := service.load(ctx, ttl, load)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := service.Cache.encode(e)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.Cache.Set(&cache.Item{
+		Ctx:   ctx,
+		Key:   key,
+		Value: b,
+		TTL:   hardTTL,
+	})
+	if err != nil {
+		service.logError(err)
+	}
+
+	return e, nil
+}
+
+// decode reads the entry into the value.
+func (service *FilmService) decode(e *entry, value interface{}) error {
+	if e.NotFound {
+		return sakila.ErrorNotFound
+	}
+
+	return e.Codec.Unmarshal(e.Value, value)
+}
+
+// encode returns the envelope of the entry, with its value compressed when it
+// reaches the compression threshold.
+func (cache *Cache) encode(e *entry) ([]byte, error) {
+	codec := e.Codec
+	if codec == nil {
+		codec = cache.codec()
+	}
+
+	codecID, err := codecID(codec)
+	if err != nil {
+		return nil, err
+	}
+
+	compressionID, value := compressionIDNone, e.Value
+
+	if len(value) >= cache.compressionThreshold() {
+		if compressionID, value, err = compress(cache.Compression, value); err != nil {
+			return nil, err
+		}
+	}
+
+	b := make([]byte, envelopeHeaderSize, envelopeHeaderSize+len(value))
+	b[0] = envelopeVersion
+	b[1] = codecID
+	b[2] = compressionID
+	binary.BigEndian.PutUint16(b[3:5], SchemaVersion)
+
+	if e.NotFound {
+		b[5] = envelopeFlagNotFound
+	}
+
+	binary.BigEndian.PutUint64(b[6:14], uint64(e.FreshUntil.UnixNano()))
+
+	return append(b, value...), nil
+}
+
+// decode returns the entry of the envelope. It returns errOutdatedEntry for
+// the envelopes of other envelope or schema versions.
+func (cache *Cache) decode(b []byte) (*entry, error) {
+	if len(b) < envelopeHeaderSize || b[0] != envelopeVersion ||
+		binary.BigEndian.Uint16(b[3:5]) != SchemaVersion {
+		return nil, errOutdatedEntry
+	}
+
+	codec, err := codecByID(b[1])
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := decompress(b[2], b[envelopeHeaderSize:])
+	if err != nil {
+		return nil, fmt.Errorf("decompress cache entry: %w", err)
+	}
+
+	return &entry{
+		Codec:      codec,
+		Value:      value,
+		NotFound:   b[5]&envelopeFlagNotFound != 0,
+		FreshUntil: time.Unix(0, int64(binary.BigEndian.Uint64(b[6:14]))),
+	}, nil
+}
@@ -0,0 +1,96 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+)
+
+// DefaultFilmEventChannel is the default film event pub/sub channel.
+const DefaultFilmEventChannel = "film_events"
+
+// FilmEventRelay publishes film events to a Redis pub/sub channel and relays
+// the events received on it to a local publisher, so that every service
+// replica receives the same events.
+type FilmEventRelay struct {
+	Cache          *Cache
+	Channel        string
+	CacheKeyPrefix string
+	Publisher      sakila.FilmEventPublisher
+	Logger         sakila.Logger
+}
+
+// PublishFilmEvent publishes a film event to the channel. Events published
+// through a local cache are relayed directly to the local publisher.
+func (relay *FilmEventRelay) PublishFilmEvent(ctx context.Context, event *sakila.FilmEvent) error {
+	if relay.Cache.client == nil {
+		return relay.Publisher.PublishFilmEvent(ctx, event)
+	}
+
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return relay.Cache.client.Publish(ctx, relay.channel(), b).Err()
+}
+
+// Listen relays the events received on the channel until the context is done.
+func (relay *FilmEventRelay) Listen(ctx context.Context) error {
+	if relay.Cache.client == nil {
+		<-ctx.Done()
+		return nil
+	}
+
+	pubSub := relay.Cache.client.Subscribe(ctx, relay.channel())
+
+	//nolint:errcheck
+	defer pubSub.Close()
+
+	if _, err := pubSub.Receive(ctx); err != nil {
+		return err
+	}
+
+	messages := pubSub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message, ok := <-messages:
+			if !ok {
+				return nil
+			}
+
+			var event sakila.FilmEvent
+			if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+				relay.logError(err)
+				continue
+			}
+
+			if err := relay.Publisher.PublishFilmEvent(ctx, &event); err != nil {
+				relay.logError(err)
+			}
+		}
+	}
+}
+
+func (relay *FilmEventRelay) channel() string {
+	channel := relay.Channel
+	if channel == "" {
+		channel = DefaultFilmEventChannel
+	}
+
+	if prefix := relay.CacheKeyPrefix; prefix != "" {
+		return prefix + "::" + channel
+	}
+
+	return channel
+}
+
+func (relay *FilmEventRelay) logError(err error) {
+	if logger := relay.Logger; logger != nil {
+		logger.Error(err)
+	}
+}
@@ -3,14 +3,15 @@ package redis
 import (
 	"context"
 	"errors"
-	"fmt"
+	"math/rand"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/nickmro/sakila-service-film/sakila"
-
-	"github.com/go-redis/cache/v8"
 )
 
 // FilmService is a cached film service.
@@ -18,25 +19,90 @@ type FilmService struct {
 	sakila.FilmService
 	Cache          *Cache
 	CacheKeyPrefix string
-	TTL            time.Duration
+	// TTL is the TTL of the cached items, unless overridden by operation in
+	// TTLs.
+	TTL  time.Duration
+	TTLs TTLs
+	// TTLJitter is the maximum random duration added to the TTLs, so that
+	// items cached together do not expire together.
+	TTLJitter time.Duration
+	// StaleTTL is how long items are served stale past their TTL while they
+	// are refreshed in the background. Stale items are not served when it is
+	// zero.
+	StaleTTL time.Duration
+	// NegativeTTL is how long not found results are cached. They are not
+	// cached when it is zero.
+	NegativeTTL time.Duration
+	// RecordAccesses records the accessed films and first pages of film lists
+	// for a Warmer.
+	RecordAccesses bool
 	Logger         sakila.Logger
+
+	reloadedTTL atomic.Value
+	refreshing  sync.Map
+	accesses    accessCounts
+}
+
+// TTLs are the TTLs of the cached items by operation. The service TTL is used
+// for the zero TTLs.
+type TTLs struct {
+	Film       time.Duration
+	Films      time.Duration
+	FilmActors time.Duration
+}
+
+// SetTTL sets the TTL of the films cached from now on. Unlike setting the TTL
+// field, it is safe to call while the service is in use.
+func (service *FilmService) SetTTL(ttl time.Duration) {
+	service.reloadedTTL.Store(ttl)
+}
+
+// ttl returns the TTL of the cached films.
+func (service *FilmService) ttl() time.Duration {
+	if ttl, ok := service.reloadedTTL.Load().(time.Duration); ok {
+		return ttl
+	}
+
+	return service.TTL
+}
+
+// itemTTL returns the TTL of an item cached by an operation, with jitter.
+func (service *FilmService) itemTTL(operationTTL time.Duration) time.Duration {
+	ttl := operationTTL
+	if ttl <= 0 {
+		ttl = service.ttl()
+	}
+
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	if jitter := service.TTLJitter; jitter > 0 && ttl > 0 {
+		ttl += time.Duration(rand.Int63n(int64(jitter) + 1))
+	}
+
+	return ttl
 }
 
 // GetFilm returns a film from the cache.
 func (service *FilmService) GetFilm(ctx context.Context, id int) (*sakila.Film, error) {
+	film, err := service.cachedFilm(ctx, id)
+	if err == nil {
+		service.recordFilmAccess(id)
+	}
+
+	return film, err
+}
+
+// cachedFilm returns a film from the cache without recording the access.
+func (service *FilmService) cachedFilm(ctx context.Context, id int) (*sakila.Film, error) {
 	var film sakila.Film
 
-	item := &cache.Item{
-		Ctx:   ctx,
-		Key:   service.filmCacheKey(id),
-		Value: &film,
-		Do: func(i *cache.Item) (interface{}, error) {
-			return service.FilmService.GetFilm(ctx, id)
-		},
-		TTL: service.TTL,
-	}
+	key := service.filmCacheKey(id)
 
-	err := service.Cache.Once(item)
+	err := service.get(ctx, key, &film, service.TTLs.Film, func(ctx context.Context) (interface{}, error) {
+		return service.FilmService.GetFilm(ctx, id)
+	})
 	if err != nil && errors.Is(err, sakila.ErrorNotFound) {
 		return nil, sakila.ErrorNotFound
 	} else if err != nil {
@@ -49,19 +115,76 @@ func (service *FilmService) GetFilm(ctx context.Context, id int) (*sakila.Film,
 
 // GetFilms returns films from the cache.
 func (service *FilmService) GetFilms(ctx context.Context, params sakila.FilmParams) ([]*sakila.Film, error) {
+	films, err := service.cachedFilms(ctx, params)
+	if err == nil && len(films) > 0 {
+		service.recordListAccess(params, nil)
+	}
+
+	return films, err
+}
+
+// cachedFilms returns films from the cache without recording the access.
+func (service *FilmService) cachedFilms(ctx context.Context, params sakila.FilmParams) ([]*sakila.Film, error) {
 	var films []*sakila.Film
 
-	item := &cache.Item{
-		Ctx:   ctx,
-		Key:   service.filmsCacheKey(params),
-		Value: &films,
-		Do: func(i *cache.Item) (interface{}, error) {
-			return service.FilmService.GetFilms(ctx, params)
-		},
-		TTL: service.TTL,
+	key := service.filmsCacheKey(params)
+
+	err := service.get(ctx, key, &films, service.TTLs.Films, func(ctx context.Context) (interface{}, error) {
+		films, err := service.FilmService.GetFilms(ctx, params)
+		if err == nil {
+			service.indexFilms(ctx, key, params, films)
+		}
+
+		return films, err
+	})
+	if err != nil && errors.Is(err, sakila.ErrorNotFound) {
+		return nil, sakila.ErrorNotFound
+	} else if err != nil {
+		service.logError(err)
+		return nil, sakila.ErrorInternal
 	}
 
-	err := service.Cache.Once(item)
+	return films, err
+}
+
+// GetFilmsWithRelations returns films with their relations from the cache.
+// The films are loaded in a single query when the film service supports it.
+func (service *FilmService) GetFilmsWithRelations(
+	ctx context.Context,
+	params sakila.FilmParams,
+	relations sakila.FilmRelations,
+) ([]*sakila.Film, error) {
+	films, err := service.cachedFilmsWithRelations(ctx, params, relations)
+	if err == nil && len(films) > 0 {
+		service.recordListAccess(params, &relations)
+	}
+
+	return films, err
+}
+
+// cachedFilmsWithRelations returns films with their relations from the cache
+// without recording the access.
+func (service *FilmService) cachedFilmsWithRelations(
+	ctx context.Context,
+	params sakila.FilmParams,
+	relations sakila.FilmRelations,
+) ([]*sakila.Film, error) {
+	var films []*sakila.Film
+
+	key := service.filmRelationsCacheKey(params, relations)
+
+	err := service.get(ctx, key, &films, service.TTLs.Films, func(ctx context.Context) (interface{}, error) {
+		films, err := service.getFilmsWithRelations(ctx, params, relations)
+		if err == nil {
+			service.indexFilms(ctx, key, params, films)
+
+			if relations.Actors {
+				service.index(ctx, key, service.actorsIndexKeys(listFilmIDs(params, films)...)...)
+			}
+		}
+
+		return films, err
+	})
 	if err != nil && errors.Is(err, sakila.ErrorNotFound) {
 		return nil, sakila.ErrorNotFound
 	} else if err != nil {
@@ -76,17 +199,45 @@ func (service *FilmService) GetFilms(ctx context.Context, params sakila.FilmPara
 func (service *FilmService) GetFilmActors(ctx context.Context, filmIDs ...int) ([]*sakila.FilmActor, error) {
 	var actors []*sakila.FilmActor
 
-	item := &cache.Item{
-		Ctx:   ctx,
-		Key:   service.actorsCacheKey(filmIDs...),
-		Value: &actors,
-		Do: func(i *cache.Item) (interface{}, error) {
-			return service.FilmService.GetFilmActors(ctx, filmIDs...)
-		},
-		TTL: service.TTL,
+	filmIDs = sortedIDs(filmIDs)
+
+	key := service.actorsCacheKey(filmIDs...)
+
+	err := service.get(ctx, key, &actors, service.TTLs.FilmActors, func(ctx context.Context) (interface{}, error) {
+		actors, err := service.FilmService.GetFilmActors(ctx, filmIDs...)
+		if err == nil {
+			service.index(ctx, key, service.actorsIndexKeys(filmIDs...)...)
+		}
+
+		return actors, err
+	})
+	if err != nil && errors.Is(err, sakila.ErrorNotFound) {
+		return nil, sakila.ErrorNotFound
+	} else if err != nil {
+		service.logError(err)
+		return nil, sakila.ErrorInternal
 	}
 
-	err := service.Cache.Once(item)
+	return actors, err
+}
+
+// getActors returns actors from the cache. Actors are not indexed, since the
+// change feed does not detect their changes: the cached actors are only
+// refreshed when they expire.
+func (service *FilmService) getActors(
+	ctx context.Context,
+	actorService sakila.ActorService,
+	actorIDs ...int,
+) ([]*sakila.Actor, error) {
+	var actors []*sakila.Actor
+
+	actorIDs = sortedIDs(actorIDs)
+
+	key := service.actorListCacheKey(actorIDs...)
+
+	err := service.get(ctx, key, &actors, 0, func(ctx context.Context) (interface{}, error) {
+		return actorService.GetActors(ctx, actorIDs...)
+	})
 	if err != nil && errors.Is(err, sakila.ErrorNotFound) {
 		return nil, sakila.ErrorNotFound
 	} else if err != nil {
@@ -97,13 +248,216 @@ func (service *FilmService) GetFilmActors(ctx context.Context, filmIDs ...int) (
 	return actors, err
 }
 
+// getFilmStores returns film stores from the cache.
+func (service *FilmService) getFilmStores(
+	ctx context.Context,
+	storeService sakila.FilmStoreService,
+	filmIDs ...int,
+) ([]*sakila.FilmStore, error) {
+	var stores []*sakila.FilmStore
+
+	filmIDs = sortedIDs(filmIDs)
+
+	key := service.storesCacheKey(filmIDs...)
+
+	err := service.get(ctx, key, &stores, 0, func(ctx context.Context) (interface{}, error) {
+		stores, err := storeService.GetFilmStores(ctx, filmIDs...)
+		if err == nil {
+			service.index(ctx, key, service.storesIndexKeys(filmIDs...)...)
+		}
+
+		return stores, err
+	})
+	if err != nil && errors.Is(err, sakila.ErrorNotFound) {
+		return nil, sakila.ErrorNotFound
+	} else if err != nil {
+		service.logError(err)
+		return nil, sakila.ErrorInternal
+	}
+
+	return stores, err
+}
+
+// getFilmTranslations returns film translations from the cache.
+func (service *FilmService) getFilmTranslations(
+	ctx context.Context,
+	translationService sakila.FilmTranslationService,
+	locale string,
+	filmIDs ...int,
+) ([]*sakila.FilmTranslation, error) {
+	var translations []*sakila.FilmTranslation
+
+	filmIDs = sortedIDs(filmIDs)
+
+	key := service.translationsCacheKey(locale, filmIDs...)
+
+	err := service.get(ctx, key, &translations, 0, func(ctx context.Context) (interface{}, error) {
+		translations, err := translationService.GetFilmTranslations(ctx, locale, filmIDs...)
+		if err == nil {
+			service.index(ctx, key, service.translationsIndexKeys(filmIDs...)...)
+		}
+
+		return translations, err
+	})
+	if err != nil && errors.Is(err, sakila.ErrorNotFound) {
+		return nil, sakila.ErrorNotFound
+	} else if err != nil {
+		service.logError(err)
+		return nil, sakila.ErrorInternal
+	}
+
+	return translations, err
+}
+
+// InvalidateFilm removes the cached film and the cached film lists containing
+// it.
+func (service *FilmService) InvalidateFilm(ctx context.Context, filmID int) error {
+	return service.invalidate(ctx, service.filmIndexKey(filmID), service.filmCacheKey(filmID))
+}
+
+// InvalidateFilmActors removes the cached actor lists of the film.
+func (service *FilmService) InvalidateFilmActors(ctx context.Context, filmID int) error {
+	return service.invalidate(ctx, service.actorsIndexKey(filmID))
+}
+
+// InvalidateFilmStores removes the cached store lists of the film.
+func (service *FilmService) InvalidateFilmStores(ctx context.Context, filmID int) error {
+	return service.invalidate(ctx, service.storesIndexKey(filmID))
+}
+
+// InvalidateFilmTranslations removes the cached translations of the film in
+// every locale.
+func (service *FilmService) InvalidateFilmTranslations(ctx context.Context, filmID int) error {
+	return service.invalidate(ctx, service.translationsIndexKey(filmID))
+}
+
+// InvalidateFilmLists removes the cached film lists not filtered by film IDs,
+// whose pages change when films are created, updated or deleted: an updated
+// film may enter or leave the lists filtered by rating or special features.
+func (service *FilmService) InvalidateFilmLists(ctx context.Context) error {
+	return service.invalidate(ctx, service.listsIndexKey())
+}
+
+// getFilmsWithRelations loads films with their relations from the film
+// service, loading the relations separately when it cannot load them with the
+// films.
+func (service *FilmService) getFilmsWithRelations(
+	ctx context.Context,
+	params sakila.FilmParams,
+	relations sakila.FilmRelations,
+) ([]*sakila.Film, error) {
+	if relationService, ok := service.FilmService.(sakila.FilmRelationService); ok {
+		return relationService.GetFilmsWithRelations(ctx, params, relations)
+	}
+
+	films, err := service.FilmService.GetFilms(ctx, params)
+	if err != nil || !relations.Actors || len(films) == 0 {
+		return films, err
+	}
+
+	actors, err := service.FilmService.GetFilmActors(ctx, listFilmIDs(sakila.FilmParams{}, films)...)
+	if err != nil {
+		return nil, err
+	}
+
+	filmsMap := make(map[int]*sakila.Film, len(films))
+	for _, film := range films {
+		film.Actors = []*sakila.Actor{}
+		filmsMap[film.FilmID] = film
+	}
+
+	for _, actor := range actors {
+		if film, ok := filmsMap[actor.FilmID]; ok {
+			film.Actors = append(film.Actors, &sakila.Actor{ActorID: actor.ActorID})
+		}
+	}
+
+	return films, nil
+}
+
+// indexFilms indexes a cached film list under the films it contains, or
+// under the requested film IDs when the list is filtered by film IDs.
+func (service *FilmService) indexFilms(
+	ctx context.Context,
+	key string,
+	params sakila.FilmParams,
+	films []*sakila.Film,
+) {
+	if len(params.FilmIDs) > 0 {
+		service.index(ctx, key, service.filmIndexKeys(params.FilmIDs...)...)
+		return
+	}
+
+	service.index(ctx, key, append(service.filmIndexKeys(listFilmIDs(params, films)...), service.listsIndexKey())...)
+}
+
+// listFilmIDs returns the film IDs of a film list: the requested film IDs when the
+// list is filtered by film IDs, or the IDs of the films it contains.
+func listFilmIDs(params sakila.FilmParams, films []*sakila.Film) []int {
+	if len(params.FilmIDs) > 0 {
+		return params.FilmIDs
+	}
+
+	ids := make([]int, len(films))
+	for i := range films {
+		ids[i] = films[i].FilmID
+	}
+
+	return ids
+}
+
+// index adds the cache key to the index sets.
+func (service *FilmService) index(ctx context.Context, key string, indexKeys ...string) {
+	if err := service.Cache.addToIndexes(ctx, key, service.indexTTL(), indexKeys...); err != nil {
+		service.logError(err)
+	}
+}
+
+// invalidate removes the cache keys and the keys in the index set.
+func (service *FilmService) invalidate(ctx context.Context, indexKey string, keys ...string) error {
+	members, err := service.Cache.indexMembers(ctx, indexKey)
+	if err != nil {
+		return err
+	}
+
+	if err := service.Cache.delete(ctx, append(keys, members...)...); err != nil {
+		return err
+	}
+
+	return service.Cache.deleteIndex(ctx, indexKey)
+}
+
+// indexTTL returns the expiration of the index sets, which must outlive the
+// cached items they index.
+func (service *FilmService) indexTTL() time.Duration {
+	ttl := time.Hour
+
+	for _, itemTTL := range []time.Duration{
+		service.ttl(),
+		service.TTLs.Film,
+		service.TTLs.Films,
+		service.TTLs.FilmActors,
+	} {
+		if itemTTL += service.TTLJitter + service.StaleTTL; itemTTL > ttl {
+			ttl = itemTTL
+		}
+	}
+
+	return ttl
+}
+
 func (service *FilmService) logError(err error) {
 	if logger := service.Logger; logger != nil {
 		logger.Error(err)
 	}
 }
 
+// cacheKey returns the cache key of a readable key, prefixed with the key
+// prefix and the schema version, such as sakila::v1::film::id:1. Long keys
+// are truncated and suffixed with their hash.
 func (service *FilmService) cacheKey(key string) string {
+	key = keyVersion + "::" + readableKey(key)
+
 	if prefix := service.CacheKeyPrefix; prefix != "" {
 		return prefix + "::" + key
 	}
@@ -112,25 +466,38 @@ func (service *FilmService) cacheKey(key string) string {
 }
 
 func (service *FilmService) filmCacheKey(id int) string {
-	key := hashedKey("film::id:" + strconv.Itoa(id))
-	return service.cacheKey(key)
+	return service.cacheKey("film::id:" + strconv.Itoa(id))
 }
 
 func (service *FilmService) filmsCacheKey(params sakila.FilmParams) string {
+	return service.cacheKey(filmsKey(params))
+}
+
+func (service *FilmService) filmRelationsCacheKey(params sakila.FilmParams, relations sakila.FilmRelations) string {
+	key := filmsKey(params)
+
+	if relations.Actors {
+		key += "::relations:actors"
+	}
+
+	return service.cacheKey(key)
+}
+
+func filmsKey(params sakila.FilmParams) string {
 	b := strings.Builder{}
 
-	b.WriteString("films::")
+	b.WriteString("films")
 
 	if ids := params.FilmIDs; len(ids) > 0 {
-		b.WriteString("::ids:")
+		b.WriteString("::ids:" + joinIDs(ids))
+	}
 
-		for i := range ids {
-			if i > 0 {
-				b.WriteString(",")
-			}
+	if ratings := params.Ratings; len(ratings) > 0 {
+		b.WriteString("::ratings:" + strings.Join(sortedStrings(ratings), ","))
+	}
 
-			b.WriteString(strconv.Itoa(ids[i]))
-		}
+	if features := params.SpecialFeatures; len(features) > 0 {
+		b.WriteString("::special_features:" + strings.Join(sortedStrings(features), ","))
 	}
 
 	if limit := params.Limit; limit > 0 {
@@ -138,22 +505,155 @@ func (service *FilmService) filmsCacheKey(params sakila.FilmParams) string {
 	}
 
 	if offset := params.Offset; offset > 0 {
-		b.WriteString(fmt.Sprintf("::offset:" + strconv.Itoa(offset)))
+		b.WriteString("::offset:" + strconv.Itoa(offset))
 	}
 
-	return service.cacheKey(hashedKey(b.String()))
+	// Projected films are cached apart from full films, so that they are
+	// never served for requests of other fields.
+	if params.Projected() {
+		b.WriteString("::fields:" + strings.Join(sortedFields(params.Fields), ","))
+	}
+
+	return b.String()
 }
 
-func (service *FilmService) actorsCacheKey(filmIDs ...int) string {
+// sortedFields returns the unique fields in sorted order.
+func sortedFields(fields []sakila.FilmField) []string {
+	values := make([]string, len(fields))
+	for i := range fields {
+		values[i] = string(fields[i])
+	}
+
+	return sortedStrings(values)
+}
+
+// sortedStrings returns the unique values in sorted order.
+func sortedStrings(values []string) []string {
+	unique := map[string]bool{}
+	for _, value := range values {
+		unique[value] = true
+	}
+
+	sorted := make([]string, 0, len(unique))
+	for value := range unique {
+		sorted = append(sorted, value)
+	}
+
+	sort.Strings(sorted)
+
+	return sorted
+}
+
+// sortedIDs returns the unique IDs in sorted order, so that the same IDs in
+// any order share a cache key.
+func sortedIDs(ids []int) []int {
+	sorted := make([]int, 0, len(ids))
+
+	for _, id := range ids {
+		i := sort.SearchInts(sorted, id)
+		if i < len(sorted) && sorted[i] == id {
+			continue
+		}
+
+		sorted = append(sorted, 0)
+		copy(sorted[i+1:], sorted[i:])
+		sorted[i] = id
+	}
+
+	return sorted
+}
+
+// joinIDs returns the unique IDs in sorted order, separated by commas.
+func joinIDs(ids []int) string {
 	b := strings.Builder{}
 
-	for i := range filmIDs {
+	for i, id := range sortedIDs(ids) {
 		if i > 0 {
 			b.WriteString(",")
 		}
 
-		b.WriteString("::film_ids:" + strconv.Itoa(filmIDs[i]))
+		b.WriteString(strconv.Itoa(id))
+	}
+
+	return b.String()
+}
+
+func (service *FilmService) actorsCacheKey(filmIDs ...int) string {
+	return service.cacheKey("film_actors::film_ids:" + joinIDs(filmIDs))
+}
+
+func (service *FilmService) actorListCacheKey(actorIDs ...int) string {
+	return service.cacheKey("actors::actor_ids:" + joinIDs(actorIDs))
+}
+
+func (service *FilmService) storesCacheKey(filmIDs ...int) string {
+	return service.cacheKey("film_stores::film_ids:" + joinIDs(filmIDs))
+}
+
+func (service *FilmService) translationsCacheKey(locale string, filmIDs ...int) string {
+	return service.cacheKey("film_translations::locale:" + locale + "::film_ids:" + joinIDs(filmIDs))
+}
+
+func (service *FilmService) filmIndexKey(filmID int) string {
+	return service.cacheKey("film::id:" + strconv.Itoa(filmID) + "::films_keys")
+}
+
+func (service *FilmService) filmIndexKeys(filmIDs ...int) []string {
+	keys := make([]string, len(filmIDs))
+	for i := range filmIDs {
+		keys[i] = service.filmIndexKey(filmIDs[i])
 	}
 
-	return service.cacheKey(hashedKey(b.String()))
+	return keys
+}
+
+func (service *FilmService) actorsIndexKey(filmID int) string {
+	return service.cacheKey("film::id:" + strconv.Itoa(filmID) + "::actors_keys")
+}
+
+func (service *FilmService) actorsIndexKeys(filmIDs ...int) []string {
+	keys := make([]string, len(filmIDs))
+	for i := range filmIDs {
+		keys[i] = service.actorsIndexKey(filmIDs[i])
+	}
+
+	return keys
+}
+
+func (service *FilmService) storesIndexKey(filmID int) string {
+	return service.cacheKey("film::id:" + strconv.Itoa(filmID) + "::stores_keys")
+}
+
+func (service *FilmService) storesIndexKeys(filmIDs ...int) []string {
+	keys := make([]string, len(filmIDs))
+	for i := range filmIDs {
+		keys[i] = service.storesIndexKey(filmIDs[i])
+	}
+
+	return keys
+}
+
+func (service *FilmService) translationsIndexKey(filmID int) string {
+	return service.cacheKey("film::id:" + strconv.Itoa(filmID) + "::translations_keys")
+}
+
+func (service *FilmService) translationsIndexKeys(filmIDs ...int) []string {
+	keys := make([]string, len(filmIDs))
+	for i := range filmIDs {
+		keys[i] = service.translationsIndexKey(filmIDs[i])
+	}
+
+	return keys
+}
+
+func (service *FilmService) listsIndexKey() string {
+	return service.cacheKey("films::lists_keys")
+}
+
+func (service *FilmService) filmAccessesKey() string {
+	return service.cacheKey("warmer::films")
+}
+
+func (service *FilmService) listAccessesKey() string {
+	return service.cacheKey("warmer::film_lists")
 }
@@ -0,0 +1,259 @@
+package redis_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+	"github.com/nickmro/sakila-service-film/sakila/graphql"
+	"github.com/nickmro/sakila-service-film/sakila/memory"
+	"github.com/nickmro/sakila-service-film/sakila/redis"
+	"github.com/nickmro/sakila-service-film/sakila/sakilatest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FilmService", func() {
+	var fixture *memory.Fixture
+
+	BeforeEach(func() {
+		f, err := memory.ReadFixture(strings.NewReader(sakilatest.FixtureJSON))
+		Expect(err).ToNot(HaveOccurred())
+		fixture = f
+	})
+
+	Context("with a local cache", func() {
+		sakilatest.DescribeFilmService(func() sakila.FilmService {
+			return (&redis.FilmService{
+				FilmService: memory.NewFilmService(fixture),
+				Cache:       redis.NewLocalCache(1000, redis.DefaultTTL),
+			}).Service()
+		})
+	})
+
+	Describe("caching", func() {
+		var ctx context.Context
+		var backend *countingFilmService
+		var service *redis.FilmService
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			backend = newCountingFilmService()
+
+			service = &redis.FilmService{
+				FilmService: backend,
+				Cache:       redis.NewLocalCache(1000, redis.DefaultTTL),
+			}
+		})
+
+		It("reads a cached film without calling the film service", func() {
+			_, err := service.GetFilm(ctx, 1)
+			Expect(err).ToNot(HaveOccurred())
+
+			film, err := service.GetFilm(ctx, 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(film.Title).To(Equal("ACADEMY DINOSAUR"))
+			Expect(backend.Calls()).To(Equal(1))
+		})
+
+		It("caches projected films apart from full films", func() {
+			projected := sakila.FilmParams{FilmIDs: []int{1}, Fields: []sakila.FilmField{sakila.FilmFieldTitle}}
+
+			_, err := service.GetFilms(ctx, projected)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = service.GetFilms(ctx, sakila.FilmParams{FilmIDs: []int{1}})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = service.GetFilms(ctx, sakila.FilmParams{
+				FilmIDs: []int{1},
+				Fields:  []sakila.FilmField{sakila.FilmFieldTitle, sakila.FilmFieldTitle},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(backend.Calls()).To(Equal(2))
+		})
+
+		It("reads the cached films of the same ratings and special features in any order", func() {
+			_, err := service.GetFilms(ctx, sakila.FilmParams{
+				Ratings:         []string{sakila.RatingPG, sakila.RatingG},
+				SpecialFeatures: []string{sakila.SpecialFeatureTrailers, sakila.SpecialFeatureDeletedScenes},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = service.GetFilms(ctx, sakila.FilmParams{
+				Ratings:         []string{sakila.RatingG, sakila.RatingPG, sakila.RatingG},
+				SpecialFeatures: []string{sakila.SpecialFeatureDeletedScenes, sakila.SpecialFeatureTrailers},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(backend.Calls()).To(Equal(1))
+		})
+
+		It("reads the cached actors of the same films in any order", func() {
+			_, err := service.GetFilmActors(ctx, 1, 2)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = service.GetFilmActors(ctx, 2, 1, 2)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(backend.Calls()).To(Equal(1))
+		})
+
+		It("reads the film again after it is invalidated", func() {
+			_, err := service.GetFilm(ctx, 1)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(service.InvalidateFilm(ctx, 1)).To(Succeed())
+
+			_, err = service.GetFilm(ctx, 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(backend.Calls()).To(Equal(2))
+		})
+
+		It("reads the film lists containing a film again after it is invalidated", func() {
+			_, err := service.GetFilms(ctx, sakila.FilmParams{Ratings: []string{sakila.RatingPG}})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = service.GetFilms(ctx, sakila.FilmParams{Ratings: []string{sakila.RatingG}})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(service.InvalidateFilm(ctx, 1)).To(Succeed())
+
+			_, err = service.GetFilms(ctx, sakila.FilmParams{Ratings: []string{sakila.RatingPG}})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = service.GetFilms(ctx, sakila.FilmParams{Ratings: []string{sakila.RatingG}})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(backend.Calls()).To(Equal(3))
+		})
+	})
+
+	Describe("expiration", func() {
+		var ctx context.Context
+		var backend *countingFilmService
+		var service *redis.FilmService
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			backend = newCountingFilmService()
+
+			service = &redis.FilmService{
+				FilmService: backend,
+				Cache:       redis.NewLocalCache(1000, redis.DefaultTTL),
+				TTL:         time.Millisecond,
+				NegativeTTL: time.Minute,
+			}
+		})
+
+		It("loads an expired film again without a stale TTL", func() {
+			_, err := service.GetFilm(ctx, 1)
+			Expect(err).ToNot(HaveOccurred())
+
+			time.Sleep(time.Millisecond * 5)
+
+			film, err := service.GetFilm(ctx, 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(film.Title).To(Equal("ACADEMY DINOSAUR"))
+			Expect(backend.Calls()).To(Equal(2))
+		})
+
+		It("serves an expired film while refreshing it", func() {
+			service.StaleTTL = time.Minute
+
+			_, err := service.GetFilm(ctx, 1)
+			Expect(err).ToNot(HaveOccurred())
+
+			time.Sleep(time.Millisecond * 5)
+
+			film, err := service.GetFilm(ctx, 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(film.Title).To(Equal("ACADEMY DINOSAUR"))
+			Eventually(backend.Calls).Should(Equal(2))
+		})
+
+		It("caches not found films", func() {
+			_, err := service.GetFilm(ctx, 0)
+			Expect(err).To(MatchError(sakila.ErrorNotFound))
+
+			_, err = service.GetFilm(ctx, 0)
+			Expect(err).To(MatchError(sakila.ErrorNotFound))
+			Expect(backend.Calls()).To(Equal(1))
+		})
+
+		It("does not cache not found films without a negative TTL", func() {
+			service.NegativeTTL = 0
+
+			_, err := service.GetFilm(ctx, 0)
+			Expect(err).To(MatchError(sakila.ErrorNotFound))
+
+			_, err = service.GetFilm(ctx, 0)
+			Expect(err).To(MatchError(sakila.ErrorNotFound))
+			Expect(backend.Calls()).To(Equal(2))
+		})
+	})
+	Describe("Service", func() {
+		var filmService *memory.FilmService
+
+		BeforeEach(func() {
+			filmService = memory.NewFilmService(fixture)
+		})
+
+		It("exposes the optional lookups of the film service", func() {
+			service := (&redis.FilmService{
+				FilmService: filmService,
+				Cache:       redis.NewLocalCache(1000, redis.DefaultTTL),
+			}).Service()
+
+			_, ok := service.(sakila.ActorService)
+			Expect(ok).To(BeTrue())
+			_, ok = service.(sakila.FilmStoreService)
+			Expect(ok).To(BeTrue())
+			_, ok = service.(sakila.FilmTranslationService)
+			Expect(ok).To(BeTrue())
+
+			schema, err := graphql.NewSchema(service)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(schema.SDL()).To(ContainSubstring("stores: [Store!]!"))
+		})
+
+		It("leaves the stores out of the schema when the film service does not find film stores", func() {
+			service := (&redis.FilmService{
+				FilmService: struct {
+					sakila.FilmService
+					sakila.ActorService
+					sakila.FilmTranslationService
+				}{filmService, filmService, filmService},
+				Cache: redis.NewLocalCache(1000, redis.DefaultTTL),
+			}).Service()
+
+			_, ok := service.(sakila.FilmStoreService)
+			Expect(ok).To(BeFalse())
+
+			schema, err := graphql.NewSchema(service)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(schema.SDL()).ToNot(ContainSubstring("stores"))
+		})
+
+		It("caches the actors", func() {
+			backend := &actorCountingFilmService{FilmService: filmService}
+
+			service, ok := (&redis.FilmService{
+				FilmService: backend,
+				Cache:       redis.NewLocalCache(1000, redis.DefaultTTL),
+			}).Service().(sakila.ActorService)
+			Expect(ok).To(BeTrue())
+
+			_, err := service.GetActors(context.Background(), 3, 1)
+			Expect(err).ToNot(HaveOccurred())
+
+			actors, err := service.GetActors(context.Background(), 1, 3)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actors).To(ConsistOf(&sakila.Actor{ActorID: 1}, &sakila.Actor{ActorID: 3}))
+			Expect(backend.calls).To(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"sync"
+	"time"
+)
+
+// localIndex holds the index sets of a local cache in process memory.
+// Expired sets are removed when they are next read or written.
+type localIndex struct {
+	mu   sync.Mutex
+	sets map[string]*localIndexSet
+}
+
+type localIndexSet struct {
+	keys    map[string]struct{}
+	expires time.Time
+}
+
+func newLocalIndex() *localIndex {
+	return &localIndex{
+		sets: map[string]*localIndexSet{},
+	}
+}
+
+func (index *localIndex) add(key string, ttl time.Duration, indexKeys ...string) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	now := time.Now()
+
+	for _, indexKey := range indexKeys {
+		set := index.set(indexKey, now)
+		if set == nil {
+			set = &localIndexSet{keys: map[string]struct{}{}}
+			index.sets[indexKey] = set
+		}
+
+		set.keys[key] = struct{}{}
+		set.expires = now.Add(ttl)
+	}
+}
+
+func (index *localIndex) members(indexKey string) []string {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	set := index.set(indexKey, time.Now())
+	if set == nil {
+		return []string{}
+	}
+
+	keys := make([]string, 0, len(set.keys))
+	for key := range set.keys {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func (index *localIndex) delete(indexKey string) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	delete(index.sets, indexKey)
+}
+
+// set returns the unexpired index set, or nil.
+func (index *localIndex) set(indexKey string, now time.Time) *localIndexSet {
+	set, ok := index.sets[indexKey]
+	if !ok {
+		return nil
+	}
+
+	if now.After(set.expires) {
+		delete(index.sets, indexKey)
+		return nil
+	}
+
+	return set
+}
@@ -0,0 +1,57 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+)
+
+// FilmCacheInvalidator removes the cache entries affected by film events
+// before passing the events on to the next publisher, so that subscribers
+// never read stale films.
+type FilmCacheInvalidator struct {
+	FilmService *FilmService
+	Publisher   sakila.FilmEventPublisher
+	Logger      sakila.Logger
+}
+
+// PublishFilmEvent invalidates the cache entries affected by the event and
+// publishes it.
+func (invalidator *FilmCacheInvalidator) PublishFilmEvent(ctx context.Context, event *sakila.FilmEvent) error {
+	if err := invalidator.invalidate(ctx, event); err != nil {
+		invalidator.logError(err)
+	}
+
+	if publisher := invalidator.Publisher; publisher != nil {
+		return publisher.PublishFilmEvent(ctx, event)
+	}
+
+	return nil
+}
+
+func (invalidator *FilmCacheInvalidator) invalidate(ctx context.Context, event *sakila.FilmEvent) error {
+	service := invalidator.FilmService
+
+	switch event.Type {
+	case sakila.FilmEventCreated, sakila.FilmEventUpdated, sakila.FilmEventDeleted:
+		if err := service.InvalidateFilm(ctx, event.FilmID); err != nil {
+			return err
+		}
+
+		return service.InvalidateFilmLists(ctx)
+	case sakila.FilmEventActorsUpdated:
+		return service.InvalidateFilmActors(ctx, event.FilmID)
+	case sakila.FilmEventInventoryUpdated:
+		return service.InvalidateFilmStores(ctx, event.FilmID)
+	case sakila.FilmEventTranslationsUpdated:
+		return service.InvalidateFilmTranslations(ctx, event.FilmID)
+	default:
+		return nil
+	}
+}
+
+func (invalidator *FilmCacheInvalidator) logError(err error) {
+	if logger := invalidator.Logger; logger != nil {
+		logger.Error(err)
+	}
+}
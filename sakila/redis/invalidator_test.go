@@ -0,0 +1,61 @@
+package redis_test
+
+import (
+	"context"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+	"github.com/nickmro/sakila-service-film/sakila/mock"
+	"github.com/nickmro/sakila-service-film/sakila/redis"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FilmCacheInvalidator", func() {
+	var ctx context.Context
+	var rating string
+	var service *redis.FilmService
+	var invalidator *redis.FilmCacheInvalidator
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		rating = sakila.RatingPG
+
+		service = &redis.FilmService{
+			FilmService: &mock.FilmService{
+				GetFilmsFn: func(ctx context.Context, params sakila.FilmParams) ([]*sakila.Film, error) {
+					films := []*sakila.Film{}
+
+					for _, r := range params.Ratings {
+						if r == rating {
+							films = append(films, &sakila.Film{FilmID: 1, Rating: &rating})
+						}
+					}
+
+					return films, nil
+				},
+			},
+			Cache: redis.NewLocalCache(1000, redis.DefaultTTL),
+		}
+
+		invalidator = &redis.FilmCacheInvalidator{FilmService: service}
+	})
+
+	It("refreshes the film lists filtered by rating when a film is updated", func() {
+		params := sakila.FilmParams{Ratings: []string{sakila.RatingG}}
+
+		films, err := service.GetFilms(ctx, params)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(films).To(BeEmpty())
+
+		rating = sakila.RatingG
+
+		event := &sakila.FilmEvent{Type: sakila.FilmEventUpdated, FilmID: 1}
+		Expect(invalidator.PublishFilmEvent(ctx, event)).To(Succeed())
+
+		films, err = service.GetFilms(ctx, params)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(films).To(HaveLen(1))
+		Expect(films[0].FilmID).To(Equal(1))
+	})
+})
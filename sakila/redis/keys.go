@@ -0,0 +1,255 @@
+package redis
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// KeyInfo describes a cached key.
+type KeyInfo struct {
+	Key  string
+	Type string
+	// TTL is negative for keys that do not expire.
+	TTL time.Duration
+	// Size is the size in bytes of a string, or the number of members of a
+	// set.
+	Size int64
+	// Entry describes the entry of a key cached by the film service, if
+	// known.
+	Entry *EntryInfo
+}
+
+// EntryInfo describes the envelope of an entry cached by the film service.
+type EntryInfo struct {
+	EnvelopeVersion int
+	SchemaVersion   int
+	Codec           string
+	Compression     string
+	NotFound        bool
+	FreshUntil      time.Time
+}
+
+// The errors of the key operations.
+var (
+	// ErrorNoRedis is the error of the key operations of a cache without
+	// Redis.
+	ErrorNoRedis = errors.New("the cache has no Redis client")
+	// ErrorNoKey is the error of a key that does not exist.
+	ErrorNoKey = errors.New("no such key")
+)
+
+const scanCount = 1000
+
+// Keys returns the keys matching the pattern, such as v1::film::*, in sorted
+// order. The pattern and the keys exclude the key prefix.
+func (cache *Cache) Keys(ctx context.Context, pattern string) ([]string, error) {
+	if cache.client == nil {
+		return nil, ErrorNoRedis
+	}
+
+	var mu sync.Mutex
+
+	keys := []string{}
+
+	scan := func(ctx context.Context, client redis.UniversalClient) error {
+		iter := client.Scan(ctx, 0, cache.prefixedKey(pattern), scanCount).Iterator()
+
+		for iter.Next(ctx) {
+			mu.Lock()
+			keys = append(keys, cache.unprefixedKey(iter.Val()))
+			mu.Unlock()
+		}
+
+		return iter.Err()
+	}
+
+	var err error
+
+	if cluster, ok := cache.client.(*redis.ClusterClient); ok {
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, master *redis.Client) error {
+			return scan(ctx, master)
+		})
+	} else {
+		err = scan(ctx, cache.client)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// Inspect returns the description of the key, which excludes the key prefix.
+func (cache *Cache) Inspect(ctx context.Context, key string) (*KeyInfo, error) {
+	if cache.client == nil {
+		return nil, ErrorNoRedis
+	}
+
+	prefixedKey := cache.prefixedKey(key)
+
+	keyType, err := cache.client.Type(ctx, prefixedKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if keyType == "none" {
+		return nil, ErrorNoKey
+	}
+
+	ttl, err := cache.client.PTTL(ctx, prefixedKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &KeyInfo{Key: key, Type: keyType, TTL: ttl}
+
+	switch keyType {
+	case "string":
+		b, err := cache.client.Get(ctx, prefixedKey).Bytes()
+		if err != nil {
+			return nil, err
+		}
+
+		info.Size = int64(len(b))
+		info.Entry = inspectEntry(b)
+	case "set":
+		info.Size, err = cache.client.SCard(ctx, prefixedKey).Result()
+	case "zset":
+		info.Size, err = cache.client.ZCard(ctx, prefixedKey).Result()
+	}
+
+	return info, err
+}
+
+// Decode returns the value of the key, which excludes the key prefix: the
+// decoded value of an entry cached by the film service, or the members of an
+// index or access set. It returns sakila.ErrorNotFound for cached not found
+// results.
+func (cache *Cache) Decode(ctx context.Context, key string) (interface{}, error) {
+	if cache.client == nil {
+		return nil, ErrorNoRedis
+	}
+
+	prefixedKey := cache.prefixedKey(key)
+
+	keyType, err := cache.client.Type(ctx, prefixedKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	switch keyType {
+	case "none":
+		return nil, ErrorNoKey
+	case "set":
+		return cache.client.SMembers(ctx, prefixedKey).Result()
+	case "zset":
+		members, err := cache.client.ZRevRangeWithScores(ctx, prefixedKey, 0, -1).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		scores := make(map[string]float64, len(members))
+		for _, member := range members {
+			if s, ok := member.Member.(string); ok {
+				scores[s] = member.Score
+			}
+		}
+
+		return scores, nil
+	}
+
+	b, err := cache.client.Get(ctx, prefixedKey).Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := cache.decode(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.NotFound {
+		return nil, sakila.ErrorNotFound
+	}
+
+	var value interface{}
+	if err := e.Codec.Unmarshal(e.Value, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// Purge removes the keys matching the pattern, which excludes the key prefix,
+// from Redis and from the in-process caches of the service replicas. It
+// returns the removed keys.
+func (cache *Cache) Purge(ctx context.Context, pattern string) ([]string, error) {
+	keys, err := cache.Keys(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixedKeys := make([]string, len(keys))
+	for i := range keys {
+		prefixedKeys[i] = cache.prefixedKey(keys[i])
+	}
+
+	if err := cache.delete(ctx, prefixedKeys...); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (cache *Cache) prefixedKey(key string) string {
+	if prefix := cache.keyPrefix; prefix != "" {
+		return prefix + "::" + key
+	}
+
+	return key
+}
+
+func (cache *Cache) unprefixedKey(key string) string {
+	if prefix := cache.keyPrefix; prefix != "" {
+		return strings.TrimPrefix(key, prefix+"::")
+	}
+
+	return key
+}
+
+// inspectEntry returns the description of an entry envelope, or nil when the
+// value is not an entry envelope of the current envelope version.
+func inspectEntry(b []byte) *EntryInfo {
+	if len(b) < envelopeHeaderSize || b[0] != envelopeVersion {
+		return nil
+	}
+
+	info := &EntryInfo{
+		EnvelopeVersion: int(b[0]),
+		SchemaVersion:   int(binary.BigEndian.Uint16(b[3:5])),
+		NotFound:        b[5]&envelopeFlagNotFound != 0,
+		FreshUntil:      time.Unix(0, int64(binary.BigEndian.Uint64(b[6:14]))),
+	}
+
+	if codec, err := codecByID(b[1]); err == nil {
+		info.Codec = codec.Name()
+	}
+
+	if int(b[2]) < len(compressions) {
+		info.Compression = string(compressions[b[2]])
+	}
+
+	return info
+}
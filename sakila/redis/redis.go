@@ -4,12 +4,30 @@ package redis
 import (
 	"crypto/sha1"
 	"fmt"
+	"strconv"
 	"time"
 )
 
 // DefaultTTL is the default cache TTL.
 const DefaultTTL = time.Minute * 5
 
+// maxReadableKeyLength is the maximum length of the readable part of the
+// cache keys.
+const maxReadableKeyLength = 128
+
+// keyVersion is the schema version segment of the cache keys.
+var keyVersion = "v" + strconv.Itoa(SchemaVersion)
+
+// readableKey returns the key, truncated and suffixed with its hash when it
+// is longer than maxReadableKeyLength.
+func readableKey(key string) string {
+	if len(key) <= maxReadableKeyLength {
+		return key
+	}
+
+	return key[:maxReadableKeyLength] + "::sha1:" + hashedKey(key)
+}
+
 func hashedKey(key string) string {
 	h := sha1.New()
 
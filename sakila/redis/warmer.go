@@ -0,0 +1,238 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+)
+
+// Warmer preloads the most requested films and first pages of film lists
+// into the cache, on startup and then periodically, so that new service
+// replicas do not load them from the database on their first requests. The
+// accesses are recorded by the film service, with RecordAccesses set, and
+// counted in Redis across the replicas.
+type Warmer struct {
+	FilmService *FilmService
+	// Films and Lists are the numbers of films and film lists preloaded.
+	Films int
+	Lists int
+	// Interval is the interval between two warm-ups. The cache is only warmed
+	// on startup when it is zero.
+	Interval time.Duration
+	// Rate is the maximum number of films and film lists loaded per second.
+	// It is unlimited when zero.
+	Rate   int
+	Logger sakila.Logger
+
+	warmedFlag int32
+}
+
+// ErrorWarming is the status of a warmer that has not yet warmed the cache.
+var ErrorWarming = errors.New("warming the cache")
+
+// listAccess is a film list access, recorded with the params and relations of
+// the list.
+type listAccess struct {
+	Params    sakila.FilmParams
+	Relations *sakila.FilmRelations `json:",omitempty"`
+}
+
+const (
+	accessTTL                   = time.Hour * 24
+	accessFlushIntervalDuration = time.Second * 10
+	// accessSetSize is the number of most accessed members kept in an access
+	// set after each flush.
+	accessSetSize = 1000
+)
+
+// Run warms the cache, then warms it again every interval and records the
+// accesses counted by the film service, until the context is done. Until a
+// warm-up succeeds, it is retried instead of recording the accesses.
+func (warmer *Warmer) Run(ctx context.Context) error {
+	if err := warmer.Warm(ctx); err != nil {
+		warmer.logError(err)
+	}
+
+	flush := time.NewTicker(accessFlushIntervalDuration)
+	defer flush.Stop()
+
+	var warm <-chan time.Time
+
+	if warmer.Interval > 0 {
+		ticker := time.NewTicker(warmer.Interval)
+		defer ticker.Stop()
+
+		warm = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-flush.C:
+			if !warmer.warmed() {
+				if err := warmer.Warm(ctx); err != nil {
+					warmer.logError(err)
+				}
+			} else if err := warmer.FilmService.flushAccesses(ctx); err != nil {
+				warmer.logError(err)
+			}
+		case <-warm:
+			if err := warmer.Warm(ctx); err != nil {
+				warmer.logError(err)
+			}
+		}
+	}
+}
+
+// Warm preloads the most requested films and film lists into the cache.
+// Films and film lists that fail to load are logged and skipped. The cache is
+// reported warmed once a warm-up succeeds.
+func (warmer *Warmer) Warm(ctx context.Context) error {
+	service := warmer.FilmService
+
+	if err := service.flushAccesses(ctx); err != nil {
+		return err
+	}
+
+	filmIDs, err := service.Cache.topAccesses(ctx, service.filmAccessesKey(), warmer.Films)
+	if err != nil {
+		return err
+	}
+
+	lists, err := service.Cache.topAccesses(ctx, service.listAccessesKey(), warmer.Lists)
+	if err != nil {
+		return err
+	}
+
+	var limit <-chan time.Time
+
+	if warmer.Rate > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(warmer.Rate))
+		defer ticker.Stop()
+
+		limit = ticker.C
+	}
+
+	members := make([]string, 0, len(filmIDs)+len(lists))
+	members = append(append(members, filmIDs...), lists...)
+
+	for i, member := range members {
+		if limit != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-limit:
+			}
+		}
+
+		if err := warmer.load(ctx, member, i < len(filmIDs)); err != nil {
+			warmer.logError(err)
+		}
+	}
+
+	warmer.logInfo("cache: warmed", len(filmIDs), "films and", len(lists), "film lists")
+
+	atomic.StoreInt32(&warmer.warmedFlag, 1)
+
+	return nil
+}
+
+// Status returns ErrorWarming until the cache is first warmed.
+func (warmer *Warmer) Status() (interface{}, error) {
+	if !warmer.warmed() {
+		return nil, ErrorWarming
+	}
+
+	return nil, nil
+}
+
+func (warmer *Warmer) warmed() bool {
+	return atomic.LoadInt32(&warmer.warmedFlag) == 1
+}
+
+// load loads a film, or a film list, through the cache, without recording
+// the access.
+func (warmer *Warmer) load(ctx context.Context, member string, film bool) error {
+	service := warmer.FilmService
+
+	if film {
+		filmID, err := strconv.Atoi(member)
+		if err != nil {
+			return err
+		}
+
+		if _, err := service.cachedFilm(ctx, filmID); err != nil && !errors.Is(err, sakila.ErrorNotFound) {
+			return err
+		}
+
+		return nil
+	}
+
+	var list listAccess
+	if err := json.Unmarshal([]byte(member), &list); err != nil {
+		return err
+	}
+
+	if list.Relations != nil {
+		_, err := service.cachedFilmsWithRelations(ctx, list.Params, *list.Relations)
+		return err
+	}
+
+	_, err := service.cachedFilms(ctx, list.Params)
+
+	return err
+}
+
+func (warmer *Warmer) logInfo(args ...interface{}) {
+	if logger := warmer.Logger; logger != nil {
+		logger.Info(args...)
+	}
+}
+
+func (warmer *Warmer) logError(err error) {
+	if logger := warmer.Logger; logger != nil {
+		logger.Error(err)
+	}
+}
+
+// recordFilmAccess counts an access to the film. Only the films found are
+// recorded.
+func (service *FilmService) recordFilmAccess(filmID int) {
+	if service.RecordAccesses {
+		service.accesses.add(service.filmAccessesKey(), strconv.Itoa(filmID))
+	}
+}
+
+// recordListAccess counts an access to the film list, if it is the first page
+// of a list not filtered by film IDs. Only the lists of films found are
+// recorded.
+func (service *FilmService) recordListAccess(params sakila.FilmParams, relations *sakila.FilmRelations) {
+	if !service.RecordAccesses || len(params.FilmIDs) > 0 || params.Offset > 0 {
+		return
+	}
+
+	b, err := json.Marshal(listAccess{Params: params, Relations: relations})
+	if err != nil {
+		service.logError(err)
+		return
+	}
+
+	service.accesses.add(service.listAccessesKey(), string(b))
+}
+
+// flushAccesses adds the counted accesses to the access sets of the cache,
+// keeping the most accessed members of each set.
+func (service *FilmService) flushAccesses(ctx context.Context) error {
+	counts := service.accesses.drain()
+	if len(counts) == 0 {
+		return nil
+	}
+
+	return service.Cache.addAccesses(ctx, counts, accessSetSize, accessTTL)
+}
@@ -0,0 +1,127 @@
+package redis_test
+
+import (
+	"context"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+	"github.com/nickmro/sakila-service-film/sakila/redis"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Warmer", func() {
+	var ctx context.Context
+	var service *redis.FilmService
+	var warmer *redis.Warmer
+	var backend *countingFilmService
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		backend = newCountingFilmService()
+
+		service = &redis.FilmService{
+			FilmService:    backend,
+			Cache:          redis.NewLocalCache(1000, redis.DefaultTTL),
+			RecordAccesses: true,
+		}
+
+		warmer = &redis.Warmer{
+			FilmService: service,
+			Films:       1,
+			Lists:       1,
+		}
+	})
+
+	It("reports the cache warming until it is warmed", func() {
+		_, err := warmer.Status()
+		Expect(err).To(MatchError(redis.ErrorWarming))
+
+		Expect(warmer.Warm(ctx)).To(Succeed())
+
+		_, err = warmer.Status()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("reports the cache warming while warming fails", func() {
+		_, err := service.GetFilm(ctx, 1)
+		Expect(err).ToNot(HaveOccurred())
+
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		warmer.Rate = 1
+
+		Expect(warmer.Warm(canceledCtx)).To(MatchError(context.Canceled))
+
+		_, err = warmer.Status()
+		Expect(err).To(MatchError(redis.ErrorWarming))
+	})
+
+	It("preloads the most requested films and film lists", func() {
+		for _, filmID := range []int{1, 2, 1} {
+			_, err := service.GetFilm(ctx, filmID)
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		_, err := service.GetFilms(ctx, sakila.FilmParams{Ratings: []string{sakila.RatingPG}, Limit: 10})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(service.InvalidateFilm(ctx, 1)).To(Succeed())
+		Expect(service.InvalidateFilm(ctx, 2)).To(Succeed())
+		Expect(service.InvalidateFilmLists(ctx)).To(Succeed())
+
+		backend.Reset()
+
+		Expect(warmer.Warm(ctx)).To(Succeed())
+		Expect(backend.FilmIDs()).To(Equal([]int{1}))
+		Expect(backend.Lists()).To(Equal(1))
+	})
+
+	It("does not record the later pages of film lists", func() {
+		_, err := service.GetFilms(ctx, sakila.FilmParams{Limit: 10, Offset: 10})
+		Expect(err).ToNot(HaveOccurred())
+
+		backend.Reset()
+
+		Expect(warmer.Warm(ctx)).To(Succeed())
+		Expect(backend.Lists()).To(BeZero())
+	})
+
+	It("does not record the films it preloads", func() {
+		_, err := service.GetFilm(ctx, 1)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(service.InvalidateFilm(ctx, 1)).To(Succeed())
+		Expect(warmer.Warm(ctx)).To(Succeed())
+
+		for i := 0; i < 2; i++ {
+			_, err = service.GetFilm(ctx, 2)
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		Expect(service.InvalidateFilm(ctx, 2)).To(Succeed())
+
+		backend.Reset()
+
+		Expect(warmer.Warm(ctx)).To(Succeed())
+		Expect(backend.FilmIDs()).To(Equal([]int{2}))
+	})
+
+	It("does not record the films not found", func() {
+		for i := 0; i < 2; i++ {
+			_, err := service.GetFilm(ctx, 1000)
+			Expect(err).To(MatchError(sakila.ErrorNotFound))
+		}
+
+		_, err := service.GetFilm(ctx, 1)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(service.InvalidateFilm(ctx, 1)).To(Succeed())
+
+		backend.Reset()
+
+		Expect(warmer.Warm(ctx)).To(Succeed())
+		Expect(backend.FilmIDs()).To(Equal([]int{1}))
+	})
+})
@@ -0,0 +1,293 @@
+package sakilatest
+
+import (
+	"context"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+// DescribeFilmService declares the conformance specs of a film service loaded
+// with the Fixture dataset, which every implementation must pass. The service
+// is created before each spec, and must also implement sakila.ActorService,
+// sakila.FilmStoreService and sakila.FilmTranslationService.
+func DescribeFilmService(newService func() sakila.FilmService) bool {
+	return Describe("FilmService", func() {
+		var service sakila.FilmService
+		var ctx context.Context
+
+		BeforeEach(func() {
+			service = newService()
+			ctx = context.Background()
+		})
+
+		Describe("GetFilm", func() {
+			It("returns the film", func() {
+				film, err := service.GetFilm(ctx, 1)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(film.FilmID).To(Equal(1))
+				Expect(film.Title).To(Equal("ACADEMY DINOSAUR"))
+				Expect(film.Description).To(PointTo(Equal("A Epic Drama of a Feminist And a Mad Scientist")))
+				Expect(film.ReleaseYear).To(PointTo(Equal(2006)))
+				Expect(film.LanguageID).To(Equal(1))
+				Expect(film.OriginalLanguageID).To(PointTo(Equal(2)))
+				Expect(film.RentalDuration).To(Equal(6))
+				Expect(film.RentalRate).To(Equal(sakila.Money(99)))
+				Expect(film.Length).To(PointTo(Equal(86)))
+				Expect(film.ReplacementCost).To(Equal(sakila.Money(2099)))
+				Expect(film.Rating).To(PointTo(Equal(sakila.RatingPG)))
+				Expect(film.SpecialFeatures).To(Equal([]string{
+					sakila.SpecialFeatureDeletedScenes,
+					sakila.SpecialFeatureBehindTheScenes,
+				}))
+				Expect(film.LastUpdate.IsZero()).To(BeFalse())
+			})
+
+			It("returns the null columns as nil", func() {
+				film, err := service.GetFilm(ctx, 6)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(film.Description).To(BeNil())
+				Expect(film.ReleaseYear).To(BeNil())
+				Expect(film.OriginalLanguageID).To(BeNil())
+				Expect(film.Length).To(BeNil())
+				Expect(film.SpecialFeatures).To(BeEmpty())
+			})
+
+			It("returns a not found error for an unknown film", func() {
+				film, err := service.GetFilm(ctx, 1000)
+				Expect(err).To(MatchError(sakila.ErrorNotFound))
+				Expect(film).To(BeNil())
+			})
+
+			It("returns a not found error for a zero ID", func() {
+				film, err := service.GetFilm(ctx, 0)
+				Expect(err).To(MatchError(sakila.ErrorNotFound))
+				Expect(film).To(BeNil())
+			})
+		})
+
+		Describe("GetFilms", func() {
+			It("returns the films ordered by ID", func() {
+				films, err := service.GetFilms(ctx, sakila.FilmParams{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(filmIDs(films)).To(Equal([]int{1, 2, 3, 4, 5, 6}))
+			})
+
+			It("returns the films with the given IDs", func() {
+				films, err := service.GetFilms(ctx, sakila.FilmParams{FilmIDs: []int{4, 2, 1000}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(filmIDs(films)).To(Equal([]int{2, 4}))
+			})
+
+			It("returns the films matching any of the ratings", func() {
+				films, err := service.GetFilms(ctx, sakila.FilmParams{
+					Ratings: []string{sakila.RatingPG, sakila.RatingNC17},
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(filmIDs(films)).To(Equal([]int{1, 3, 6}))
+			})
+
+			It("returns the films matching all of the special features", func() {
+				films, err := service.GetFilms(ctx, sakila.FilmParams{
+					SpecialFeatures: []string{sakila.SpecialFeatureTrailers, sakila.SpecialFeatureDeletedScenes},
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(filmIDs(films)).To(Equal([]int{2, 3}))
+			})
+
+			It("limits the films", func() {
+				films, err := service.GetFilms(ctx, sakila.FilmParams{Limit: 2})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(filmIDs(films)).To(Equal([]int{1, 2}))
+			})
+
+			It("pages the films", func() {
+				films, err := service.GetFilms(ctx, sakila.FilmParams{Limit: 2, Offset: 3})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(filmIDs(films)).To(Equal([]int{4, 5}))
+			})
+
+			It("skips the offset without a limit", func() {
+				films, err := service.GetFilms(ctx, sakila.FilmParams{Offset: 4})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(filmIDs(films)).To(Equal([]int{5, 6}))
+			})
+
+			It("returns every film for a limit past the last film", func() {
+				films, err := service.GetFilms(ctx, sakila.FilmParams{Limit: 100})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(filmIDs(films)).To(Equal([]int{1, 2, 3, 4, 5, 6}))
+			})
+
+			It("returns an empty slice for an offset past the last film", func() {
+				films, err := service.GetFilms(ctx, sakila.FilmParams{Limit: 2, Offset: 6})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(films).To(BeEmpty())
+			})
+
+			It("pages the filtered films", func() {
+				films, err := service.GetFilms(ctx, sakila.FilmParams{
+					Ratings: []string{sakila.RatingG},
+					Limit:   1,
+					Offset:  1,
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(filmIDs(films)).To(Equal([]int{4}))
+			})
+
+			It("returns an empty slice when no films match", func() {
+				films, err := service.GetFilms(ctx, sakila.FilmParams{FilmIDs: []int{1000}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(films).ToNot(BeNil())
+				Expect(films).To(BeEmpty())
+			})
+		})
+
+		Describe("GetFilmActors", func() {
+			It("returns the actors of the films", func() {
+				actors, err := service.GetFilmActors(ctx, 1, 2)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(actors).To(ConsistOf(
+					filmActor(1, 1),
+					filmActor(1, 2),
+					filmActor(1, 3),
+					filmActor(2, 2),
+				))
+			})
+
+			It("returns the actors of a film listed twice once", func() {
+				actors, err := service.GetFilmActors(ctx, 2, 2)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(actors).To(ConsistOf(filmActor(2, 2)))
+			})
+
+			It("returns an empty slice for a film without actors", func() {
+				actors, err := service.GetFilmActors(ctx, 6)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(actors).To(BeEmpty())
+			})
+
+			It("returns an empty slice for no films", func() {
+				actors, err := service.GetFilmActors(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(actors).To(BeEmpty())
+			})
+		})
+
+		Describe("GetActors", func() {
+			var actorService sakila.ActorService
+
+			BeforeEach(func() {
+				var ok bool
+				actorService, ok = service.(sakila.ActorService)
+				Expect(ok).To(BeTrue())
+			})
+
+			It("returns the actors", func() {
+				actors, err := actorService.GetActors(ctx, 3, 1, 1000)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(actors).To(ConsistOf(
+					&sakila.Actor{ActorID: 1},
+					&sakila.Actor{ActorID: 3},
+				))
+			})
+
+			It("returns an empty slice for no actors", func() {
+				actors, err := actorService.GetActors(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(actors).To(BeEmpty())
+			})
+		})
+
+		Describe("GetFilmStores", func() {
+			var storeService sakila.FilmStoreService
+
+			BeforeEach(func() {
+				var ok bool
+				storeService, ok = service.(sakila.FilmStoreService)
+				Expect(ok).To(BeTrue())
+			})
+
+			It("returns each store stocking the films once", func() {
+				stores, err := storeService.GetFilmStores(ctx, 1, 3)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stores).To(ConsistOf(
+					filmStore(1, 1),
+					filmStore(1, 2),
+					filmStore(3, 2),
+				))
+			})
+
+			It("returns an empty slice for a film out of stock", func() {
+				stores, err := storeService.GetFilmStores(ctx, 6)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stores).To(BeEmpty())
+			})
+
+			It("returns an empty slice for no films", func() {
+				stores, err := storeService.GetFilmStores(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stores).To(BeEmpty())
+			})
+		})
+
+		Describe("GetFilmTranslations", func() {
+			var translationService sakila.FilmTranslationService
+
+			BeforeEach(func() {
+				var ok bool
+				translationService, ok = service.(sakila.FilmTranslationService)
+				Expect(ok).To(BeTrue())
+			})
+
+			It("returns the translations of the films for the locale", func() {
+				translations, err := translationService.GetFilmTranslations(ctx, LocaleFrench, 1, 2, 3)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(translations).To(HaveLen(2))
+
+				byFilm := map[int]*sakila.FilmTranslation{}
+				for _, translation := range translations {
+					Expect(translation.Locale).To(Equal(LocaleFrench))
+					byFilm[translation.FilmID] = translation
+				}
+
+				Expect(byFilm[1].Title).To(Equal("ACADÉMIE DINOSAURE"))
+				Expect(byFilm[1].Description).ToNot(BeNil())
+				Expect(byFilm[2].Title).To(Equal("AS GOLDFINGER"))
+				Expect(byFilm[2].Description).To(BeNil())
+			})
+
+			It("returns an empty slice for an untranslated locale", func() {
+				translations, err := translationService.GetFilmTranslations(ctx, "es", 1, 2)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(translations).To(BeEmpty())
+			})
+
+			It("returns an empty slice for no films", func() {
+				translations, err := translationService.GetFilmTranslations(ctx, LocaleFrench)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(translations).To(BeEmpty())
+			})
+		})
+	})
+}
+
+func filmIDs(films []*sakila.Film) []int {
+	ids := make([]int, len(films))
+	for i := range films {
+		ids[i] = films[i].FilmID
+	}
+
+	return ids
+}
+
+func filmActor(filmID, actorID int) *sakila.FilmActor {
+	return &sakila.FilmActor{Actor: sakila.Actor{ActorID: actorID}, FilmID: filmID}
+}
+
+func filmStore(filmID, storeID int) *sakila.FilmStore {
+	return &sakila.FilmStore{Store: sakila.Store{StoreID: storeID}, FilmID: filmID}
+}
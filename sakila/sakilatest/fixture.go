@@ -0,0 +1,110 @@
+// Package sakilatest provides the conformance specs shared by the film service
+// implementations, and the dataset they run against.
+package sakilatest
+
+import (
+	_ "embed" // fixture JSON
+
+	"github.com/nickmro/sakila-service-film/sakila/dataset"
+)
+
+// The fixture locales.
+const (
+	LocaleFrench = "fr"
+	LocaleGerman = "de"
+)
+
+// FixtureJSON is the Fixture dataset in the JSON fixture format of the
+// memory package.
+//
+//go:embed fixture.json
+var FixtureJSON string
+
+// Fixture returns the dataset the shared specs run against, in table
+// insertion order.
+func Fixture() []*dataset.Table {
+	return []*dataset.Table{
+		{
+			Name:    "language",
+			Columns: []string{"language_id", "name"},
+			Rows: [][]interface{}{
+				{1, "English"},
+				{2, "French"},
+			},
+		},
+		{
+			Name:    "actor",
+			Columns: []string{"actor_id", "first_name", "last_name"},
+			Rows: [][]interface{}{
+				{1, "PENELOPE", "GUINESS"},
+				{2, "NICK", "WAHLBERG"},
+				{3, "ED", "CHASE"},
+			},
+		},
+		{
+			Name: "film",
+			Columns: []string{
+				"film_id", "title", "description", "release_year", "language_id", "original_language_id",
+				"rental_duration", "rental_rate", "length", "replacement_cost", "rating", "special_features",
+			},
+			Rows: [][]interface{}{
+				{
+					1, "ACADEMY DINOSAUR", "A Epic Drama of a Feminist And a Mad Scientist", 2006, 1, 2,
+					6, "0.99", 86, "20.99", "PG", "Deleted Scenes,Behind the Scenes",
+				},
+				{
+					2, "ACE GOLDFINGER", "A Astounding Epistle of a Database Administrator", 2006, 1, nil,
+					3, "4.99", 48, "12.99", "G", "Trailers,Deleted Scenes",
+				},
+				{
+					3, "ADAPTATION HOLES", "A Astounding Reflection of a Lumberjack", 2006, 1, nil,
+					7, "2.99", 50, "18.99", "NC-17", "Trailers,Deleted Scenes",
+				},
+				{
+					4, "AFFAIR PREJUDICE", "A Fanciful Documentary of a Frisbee", 2006, 1, nil,
+					5, "2.99", 117, "26.99", "G", "Commentaries,Behind the Scenes",
+				},
+				{
+					5, "AFRICAN EGG", "A Fast-Paced Documentary of a Pastry Chef", 2006, 1, nil,
+					6, "2.99", 130, "22.99", "G", "Deleted Scenes",
+				},
+				{
+					6, "AGENT TRUMAN", nil, nil, 1, nil,
+					3, "2.99", nil, "17.99", "PG", nil,
+				},
+			},
+		},
+		{
+			Name:    "film_actor",
+			Columns: []string{"actor_id", "film_id"},
+			Rows: [][]interface{}{
+				{1, 1},
+				{2, 1},
+				{3, 1},
+				{2, 2},
+				{1, 3},
+			},
+		},
+		{
+			Name:    "inventory",
+			Columns: []string{"film_id", "store_id"},
+			Rows: [][]interface{}{
+				{1, 1},
+				{1, 1},
+				{1, 2},
+				{2, 2},
+				{3, 2},
+				{3, 2},
+			},
+		},
+		{
+			Name:    "film_translation",
+			Columns: []string{"film_id", "locale", "title", "description"},
+			Rows: [][]interface{}{
+				{1, LocaleFrench, "ACADÉMIE DINOSAURE", "Un drame épique d'une féministe et d'un savant fou"},
+				{2, LocaleFrench, "AS GOLDFINGER", nil},
+				{1, LocaleGerman, "AKADEMIE DINOSAURIER", nil},
+			},
+		},
+	}
+}
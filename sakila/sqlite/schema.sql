@@ -0,0 +1,78 @@
+-- The Sakila tables read by the film service. Money amounts are stored as
+-- text to keep their exact decimal value.
+CREATE TABLE IF NOT EXISTS language (
+  language_id INTEGER PRIMARY KEY,
+  name TEXT NOT NULL,
+  last_update TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS actor (
+  actor_id INTEGER PRIMARY KEY,
+  first_name TEXT NOT NULL,
+  last_name TEXT NOT NULL,
+  last_update TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS category (
+  category_id INTEGER PRIMARY KEY,
+  name TEXT NOT NULL,
+  last_update TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS film (
+  film_id INTEGER PRIMARY KEY,
+  title TEXT NOT NULL,
+  description TEXT DEFAULT NULL,
+  release_year INTEGER DEFAULT NULL,
+  language_id INTEGER NOT NULL REFERENCES language (language_id),
+  original_language_id INTEGER DEFAULT NULL REFERENCES language (language_id),
+  rental_duration INTEGER NOT NULL DEFAULT 3,
+  rental_rate TEXT NOT NULL DEFAULT '4.99',
+  length INTEGER DEFAULT NULL,
+  replacement_cost TEXT NOT NULL DEFAULT '19.99',
+  rating TEXT DEFAULT 'G' CHECK (rating IN ('G', 'PG', 'PG-13', 'R', 'NC-17')),
+  special_features TEXT DEFAULT NULL,
+  last_update TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_title ON film (title);
+
+CREATE TABLE IF NOT EXISTS film_actor (
+  actor_id INTEGER NOT NULL REFERENCES actor (actor_id),
+  film_id INTEGER NOT NULL REFERENCES film (film_id),
+  last_update TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (actor_id, film_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fk_film_actor_film_id ON film_actor (film_id);
+
+CREATE TABLE IF NOT EXISTS film_category (
+  film_id INTEGER NOT NULL REFERENCES film (film_id),
+  category_id INTEGER NOT NULL REFERENCES category (category_id),
+  last_update TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (film_id, category_id)
+);
+
+CREATE TABLE IF NOT EXISTS film_text (
+  film_id INTEGER PRIMARY KEY,
+  title TEXT NOT NULL,
+  description TEXT
+);
+
+CREATE TABLE IF NOT EXISTS inventory (
+  inventory_id INTEGER PRIMARY KEY,
+  film_id INTEGER NOT NULL REFERENCES film (film_id),
+  store_id INTEGER NOT NULL,
+  last_update TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_store_id_film_id ON inventory (store_id, film_id);
+
+CREATE TABLE IF NOT EXISTS film_translation (
+  film_id INTEGER NOT NULL REFERENCES film (film_id) ON DELETE CASCADE,
+  locale TEXT NOT NULL,
+  title TEXT NOT NULL,
+  description TEXT DEFAULT NULL,
+  last_update TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (film_id, locale)
+);
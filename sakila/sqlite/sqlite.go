@@ -0,0 +1,190 @@
+// Package sqlite handles operations on an embedded SQLite copy of the Sakila
+// database, for running the service without MySQL. The driver requires cgo.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed" // schema
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/nickmro/sakila-service-film/sakila/dataset"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// DriverName is the name of the SQLite driver, which adds MySQL's
+// FIND_IN_SET and JSON_ARRAYAGG functions so that MySQL film queries run
+// unchanged.
+const DriverName = "sakila_sqlite3"
+
+// MemoryDataSourceName is the data source name of an in-memory database.
+const MemoryDataSourceName = ":memory:"
+
+// seedBatchSize is the number of rows per generated INSERT statement.
+const seedBatchSize = 500
+
+const pingTimeoutDuration = time.Second * 5
+
+//go:embed schema.sql
+var schema string
+
+func init() {
+	sql.Register(DriverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			if err := conn.RegisterFunc("FIND_IN_SET", findInSet, true); err != nil {
+				return err
+			}
+
+			return conn.RegisterAggregator("JSON_ARRAYAGG", newJSONArrayAgg, true)
+		},
+	})
+}
+
+// DB is a SQLite DB connection.
+type DB struct {
+	*sql.DB
+}
+
+// Open opens a SQLite database file, or an in-memory database for
+// MemoryDataSourceName.
+func Open(dataSourceName string) (*DB, error) {
+	db, err := sql.Open(DriverName, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every connection to an in-memory database opens a new database, so
+	// the pool is limited to a single connection.
+	if dataSourceName == MemoryDataSourceName {
+		db.SetMaxOpenConns(1)
+		db.SetConnMaxLifetime(0)
+	}
+
+	return &DB{DB: db}, nil
+}
+
+// Status satisfies the health checker interface.
+func (db *DB) Status() (interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeoutDuration)
+	defer cancel()
+
+	return nil, db.DB.PingContext(ctx)
+}
+
+// Migrate creates the Sakila tables that do not exist.
+func (db *DB) Migrate(ctx context.Context) error {
+	for _, statement := range strings.Split(schema, ";") {
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Empty returns whether the database has no films.
+func (db *DB) Empty(ctx context.Context) (bool, error) {
+	var count int
+
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM film").Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count == 0, nil
+}
+
+// Seed loads a dataset in a single transaction, filling the film_text table
+// from the films.
+func (db *DB) Seed(ctx context.Context, tables []*dataset.Table) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	//nolint:errcheck
+	defer tx.Rollback()
+
+	for _, table := range tables {
+		for _, rows := range table.Batches(seedBatchSize) {
+			query, args := table.InsertQuery(rows)
+
+			if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO film_text (film_id, title, description)
+		SELECT film_id, title, description FROM film`); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// findInSet returns the 1-based position of the value in the comma separated
+// set, or 0 when the set does not contain it.
+func findInSet(value, set interface{}) int64 {
+	v, ok := textValue(value)
+	if !ok {
+		return 0
+	}
+
+	s, ok := textValue(set)
+	if !ok || s == "" {
+		return 0
+	}
+
+	for i, member := range strings.Split(s, ",") {
+		if member == v {
+			return int64(i + 1)
+		}
+	}
+
+	return 0
+}
+
+// jsonArrayAgg aggregates values into a JSON array. Like MySQL, it returns
+// NULL when there are no values.
+type jsonArrayAgg struct {
+	values []interface{}
+}
+
+func newJSONArrayAgg() *jsonArrayAgg {
+	return &jsonArrayAgg{}
+}
+
+func (agg *jsonArrayAgg) Step(value interface{}) {
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+
+	agg.values = append(agg.values, value)
+}
+
+// Done returns the JSON array. The driver returns a nil slice as NULL.
+func (agg *jsonArrayAgg) Done() ([]byte, error) {
+	if len(agg.values) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(agg.values)
+}
+
+func textValue(value interface{}) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case []byte:
+		return string(value), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,53 @@
+package sqlite_test
+
+import (
+	"context"
+
+	"github.com/nickmro/sakila-service-film/sakila"
+	"github.com/nickmro/sakila-service-film/sakila/mysql"
+	"github.com/nickmro/sakila-service-film/sakila/sakilatest"
+	"github.com/nickmro/sakila-service-film/sakila/sqlite"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SQLite", func() {
+	var db *sqlite.DB
+
+	BeforeEach(func() {
+		d, err := sqlite.Open(sqlite.MemoryDataSourceName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(d.Migrate(context.Background())).To(Succeed())
+		Expect(d.Seed(context.Background(), sakilatest.Fixture())).To(Succeed())
+		db = d
+	})
+
+	AfterEach(func() {
+		Expect(db.Close()).To(Succeed())
+	})
+
+	It("fills the film text from the seeded films", func() {
+		var title string
+
+		err := db.QueryRow("SELECT title FROM film_text WHERE film_id = 2").Scan(&title)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(title).To(Equal("ACE GOLDFINGER"))
+	})
+
+	// The serve command runs the MySQL film service on SQLite when
+	// DATABASE_DRIVER is sqlite.
+	Context("with the MySQL film service", func() {
+		sakilatest.DescribeFilmService(func() sakila.FilmService {
+			return &mysql.FilmService{DB: &mysql.DB{DB: db.DB}}
+		})
+	})
+
+	It("migrates an existing database", func() {
+		Expect(db.Migrate(context.Background())).To(Succeed())
+
+		empty, err := db.Empty(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(empty).To(BeFalse())
+	})
+})
@@ -0,0 +1,20 @@
+package sakila
+
+import "context"
+
+// Store is a sakila store.
+type Store struct {
+	StoreID int `json:"storeId"`
+}
+
+// FilmStore is a sakila store stocking a film.
+type FilmStore struct {
+	Store
+	FilmID int
+}
+
+// FilmStoreService defines the interface for a film service that finds the
+// stores stocking films.
+type FilmStoreService interface {
+	GetFilmStores(ctx context.Context, filmIDs ...int) ([]*FilmStore, error)
+}
@@ -0,0 +1,81 @@
+package sakila
+
+import (
+	"context"
+	"strings"
+)
+
+// FilmTranslation is the localized text of a film.
+type FilmTranslation struct {
+	FilmID      int     `json:"filmId"`
+	Locale      string  `json:"locale"`
+	Title       string  `json:"title"`
+	Description *string `json:"description,omitempty"`
+}
+
+// FilmTranslationService defines the interface for a film service that finds
+// film translations.
+type FilmTranslationService interface {
+	GetFilmTranslations(ctx context.Context, locale string, filmIDs ...int) ([]*FilmTranslation, error)
+}
+
+type localeContextKey struct{}
+
+// WithLocale returns a copy of the context carrying the requested locale.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeContextKey{}, locale)
+}
+
+// LocaleFromContext returns the requested locale carried by the context, or
+// an empty string when the original text is requested.
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeContextKey{}).(string)
+	return locale
+}
+
+// SupportedLocale returns the supported locale matching the locale exactly or
+// by its primary language, ignoring case. It returns an empty string, for the
+// original text, when none match or no locales are supported.
+func SupportedLocale(locale string, supported []string) string {
+	for _, s := range supported {
+		if strings.EqualFold(locale, s) {
+			return s
+		}
+	}
+
+	for _, s := range supported {
+		if strings.EqualFold(primaryLanguage(locale), s) {
+			return s
+		}
+	}
+
+	return ""
+}
+
+// LocalizedTitle returns the translated title, or the original title when
+// there is no translation.
+func (f *Film) LocalizedTitle(translation *FilmTranslation) string {
+	if translation != nil && translation.Title != "" {
+		return translation.Title
+	}
+
+	return f.Title
+}
+
+// LocalizedDescription returns the translated description, or the original
+// description when there is no translated description.
+func (f *Film) LocalizedDescription(translation *FilmTranslation) *string {
+	if translation != nil && translation.Description != nil {
+		return translation.Description
+	}
+
+	return f.Description
+}
+
+func primaryLanguage(tag string) string {
+	if i := strings.IndexByte(tag, '-'); i > 0 {
+		return tag[:i]
+	}
+
+	return tag
+}